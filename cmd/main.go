@@ -5,12 +5,16 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"strconv"
+	"time"
 
 	"frappuccino/config"
 	"frappuccino/internal/server"
 	"frappuccino/internal/svc"
+	"frappuccino/pkg/notify"
+	"frappuccino/pkg/storage"
 
 	repo "frappuccino/internal/repo"
 
@@ -18,8 +22,9 @@ import (
 )
 
 var (
-	port  int
-	dbURL string
+	port     int
+	dbURL    string
+	mediaDir string
 )
 
 func main() {
@@ -27,6 +32,7 @@ func main() {
 
 	flag.IntVar(&port, "port", config.GetEnvInt("PORT", 9090), "Port number")
 	flag.StringVar(&dbURL, "db", os.Getenv("DATABASE_URL"), "Database connection URL")
+	flag.StringVar(&mediaDir, "media", config.GetEnv("MEDIA_DIR", "media"), "Directory for uploaded images")
 	flag.Parse()
 
 	if port < 0 || port > 65535 {
@@ -47,7 +53,25 @@ func main() {
 
 	container := repo.New(db)
 
-	service := svc.NewSvc(container)
+	media, err := storage.NewLocal(mediaDir, config.GetEnv("MEDIA_BASE_URL", "/media"))
+	if err != nil {
+		log.Fatalf("Failed to initialize media storage: %v", err)
+	}
+
+	notifier, err := notify.New(notify.Config{
+		Channels:   config.GetEnv("ALERT_NOTIFIERS", "log"),
+		WebhookURL: os.Getenv("ALERT_WEBHOOK_URL"),
+		SMTPAddr:   config.GetEnv("SMTP_ADDR", "localhost:1025"),
+		EmailFrom:  config.GetEnv("ALERT_EMAIL_FROM", "frappuccino@localhost"),
+		EmailTo:    os.Getenv("ALERT_EMAIL_TO"),
+	}, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	if err != nil {
+		log.Fatalf("Failed to initialize alert notifiers: %v", err)
+	}
+
+	service := svc.NewSvc(container, media, notifier)
+
+	go service.RunPriceScheduler(time.Minute)
 
 	handler := server.New(service)
 
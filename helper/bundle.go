@@ -0,0 +1,72 @@
+package helper
+
+import (
+	"fmt"
+
+	"frappuccino/internal/models"
+)
+
+func CheckBundle(item models.MenuItem, menuItems []models.MenuItem) error {
+	if len(item.Components) == 0 {
+		return fmt.Errorf("bundle must contain at least one component")
+	}
+	if len(item.Ingredients) > 0 {
+		return fmt.Errorf("bundle cannot have its own ingredients, they come from its components")
+	}
+
+	menuMap := make(map[int]models.MenuItem)
+	for _, m := range menuItems {
+		menuMap[m.ID] = m
+	}
+
+	seen := make(map[int]bool)
+	for _, c := range item.Components {
+		component, exists := menuMap[c.MenuItemID]
+		if !exists {
+			return fmt.Errorf("bundle component with menu item ID %d not found", c.MenuItemID)
+		}
+		if component.Type == models.MenuItemBundle {
+			return fmt.Errorf("bundle component %d is a bundle itself, nested bundles are not supported", c.MenuItemID)
+		}
+		if item.ID != 0 && c.MenuItemID == item.ID {
+			return fmt.Errorf("bundle cannot contain itself")
+		}
+		if c.Quantity <= 0 {
+			return fmt.Errorf("bundle component quantity should be greater than 0, got: %d", c.Quantity)
+		}
+		if seen[c.MenuItemID] {
+			return fmt.Errorf("bundle component %d is listed more than once", c.MenuItemID)
+		}
+		seen[c.MenuItemID] = true
+	}
+	return nil
+}
+
+// ExpandRecipes добавляет к рецептурам комбо-наборов ингредиенты их компонентов,
+// умноженные на количество компонента в наборе.
+func ExpandRecipes(recipes map[int][]models.MenuItemIngredient, components map[int][]models.BundleComponent) map[int][]models.MenuItemIngredient {
+	expanded := make(map[int][]models.MenuItemIngredient, len(recipes)+len(components))
+	for id, ingredients := range recipes {
+		expanded[id] = ingredients
+	}
+
+	for bundleID, parts := range components {
+		var ingredients []models.MenuItemIngredient
+		index := make(map[string]int)
+		for _, part := range parts {
+			for _, ing := range recipes[part.MenuItemID] {
+				key := fmt.Sprintf("%d/%s", ing.IngredientID, ing.Unit)
+				qty := ing.Quantity * float64(part.Quantity)
+				if i, exists := index[key]; exists {
+					ingredients[i].Quantity += qty
+					continue
+				}
+				index[key] = len(ingredients)
+				ing.Quantity = qty
+				ingredients = append(ingredients, ing)
+			}
+		}
+		expanded[bundleID] = ingredients
+	}
+	return expanded
+}
@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"frappuccino/internal/models"
+)
+
+// EncodeLeftOverCursor упаковывает курсор в непрозрачную строку для query.
+func EncodeLeftOverCursor(c models.LeftOverCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeLeftOverCursor разбирает курсор и проверяет, что он выдан для той же
+// сортировки.
+func DecodeLeftOverCursor(cursor, sortBy, sortOrder string) (*models.LeftOverCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var c models.LeftOverCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if c.SortBy != sortBy || c.SortOrder != sortOrder {
+		return nil, fmt.Errorf("cursor was issued for sortBy=%s&sortOrder=%s", c.SortBy, c.SortOrder)
+	}
+	return &c, nil
+}
@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"math"
+	"time"
+
+	"frappuccino/internal/models"
+)
+
+// ForecastMenuDemand прогнозирует продажи позиций на days дней начиная с from.
+// Модель — скользящее среднее с недельной сезонностью: ожидаемые продажи в
+// данный день недели и час равны среднему за weeks последних таких же дней.
+// Недели без продаж тоже входят в среднее. В первый день учитываются только
+// часы начиная с текущего. Результат — позиция -> продажи по дням.
+func ForecastMenuDemand(history []models.SalesSlot, weeks int, from time.Time, days int) map[int][]float64 {
+	type slotKey struct {
+		weekday time.Weekday
+		hour    int
+	}
+	average := make(map[int]map[slotKey]float64)
+	for _, slot := range history {
+		if average[slot.MenuItemID] == nil {
+			average[slot.MenuItemID] = make(map[slotKey]float64)
+		}
+		average[slot.MenuItemID][slotKey{slot.Weekday, slot.Hour}] += slot.Quantity / float64(weeks)
+	}
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	result := make(map[int][]float64)
+	for menuItemID, slots := range average {
+		daily := make([]float64, days)
+		for key, quantity := range slots {
+			for i := 0; i < days; i++ {
+				if start.AddDate(0, 0, i).Weekday() != key.weekday {
+					continue
+				}
+				if i == 0 && key.hour < from.Hour() {
+					continue
+				}
+				daily[i] += quantity
+			}
+		}
+		result[menuItemID] = daily
+	}
+	return result
+}
+
+// ForecastIngredients переводит прогноз продаж в расход ингредиентов по рецептурам.
+func ForecastIngredients(demand map[int][]float64, recipes map[int][]models.MenuItemIngredient) map[int][]float64 {
+	result := make(map[int][]float64)
+	for menuItemID, daily := range demand {
+		for _, ing := range recipes[menuItemID] {
+			if result[ing.IngredientID] == nil {
+				result[ing.IngredientID] = make([]float64, len(daily))
+			}
+			for i, quantity := range daily {
+				result[ing.IngredientID][i] += quantity * ing.Quantity
+			}
+		}
+	}
+	for _, daily := range result {
+		for i := range daily {
+			daily[i] = math.Round(daily[i]*10000) / 10000
+		}
+	}
+	return result
+}
@@ -5,18 +5,21 @@ import (
 	"regexp"
 	"strings"
 	"sync/atomic"
+	"unicode/utf8"
 )
 
+// IsValidName допускает буквы любых алфавитов (Café, Milchkaffee, Раф), цифры,
+// пробел, подчёркивание и дефис. Длина считается в символах, а не в байтах.
 func IsValidName(name string) error {
-	nameLength := len(name)
+	nameLength := utf8.RuneCountInString(name)
 	if nameLength < 2 || nameLength > 120 {
-		return fmt.Errorf("the name must be between 2 and 63 characters long: %s", name)
+		return fmt.Errorf("the name must be between 2 and 120 characters long: %s", name)
 	}
 
-	validNameRegex := regexp.MustCompile(`^[A-Za-z0-9_ ]+$`)
+	validNameRegex := regexp.MustCompile(`^[\p{L}\p{M}\p{N}_ -]+$`)
 
 	if !validNameRegex.MatchString(name) {
-		return fmt.Errorf("name must only contain alphanumeric characters, underscores, and hyphens: %s", name)
+		return fmt.Errorf("name must only contain letters, digits, spaces, underscores, and hyphens: %s", name)
 	}
 
 	if strings.Contains(name, "..") {
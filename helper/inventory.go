@@ -3,9 +3,11 @@ package helper
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"frappuccino/internal/models"
 	"frappuccino/pkg/cerrors"
+	"frappuccino/pkg/units"
 )
 
 func CheckItemExists(items []models.InventoryItem, name, unit string) (*models.InventoryItem, error) {
@@ -44,8 +46,103 @@ func CheckerForInventItems(item models.InventoryItem) error {
 	if item.Stock <= 0 {
 		return fmt.Errorf("please specify a stock quantity for the item")
 	}
+	return checkInventFields(item)
+}
+
+// CheckImportedInventItem проверяет строку импорта склада. В отличие от
+// CheckerForInventItems остаток 0 допустим: выгрузка с закончившейся позицией
+// загружается обратно. Для поставки (delivery) количество должно быть больше 0.
+func CheckImportedInventItem(item models.InventoryItem, delivery bool) error {
+	if item.Name == "" {
+		return fmt.Errorf("please provide a name for the item")
+	}
+	if delivery && item.Stock <= 0 {
+		return fmt.Errorf("delivered stock must be greater than 0, got: %v", item.Stock)
+	}
+	if item.Stock < 0 {
+		return fmt.Errorf("stock must not be negative, got: %v", item.Stock)
+	}
+	return checkInventFields(item)
+}
+
+func checkInventFields(item models.InventoryItem) error {
 	if item.Unit == "" {
 		return fmt.Errorf("please provide a unit for the item")
 	}
+	if _, err := units.Parse(item.Unit); err != nil {
+		return fmt.Errorf("%v, see GET /units for supported units", err)
+	}
+	return CheckNutrition(item.Nutrition)
+}
+
+func CheckTransactionType(t string) error {
+	switch t {
+	case models.TransactionPurchase, models.TransactionUse, models.TransactionWaste,
+		models.TransactionAdjustment, models.TransactionTransfer, models.TransactionReturn:
+		return nil
+	}
+	return fmt.Errorf("transaction type must be one of purchase, use, waste, adjustment, transfer, return, got: %q", t)
+}
+
+// CheckTransaction проверяет знак движения: поставка только увеличивает остаток,
+// расход и списание только уменьшают; корректировка, перемещение и возврат — в обе стороны.
+func CheckTransaction(t models.InventoryTransaction) error {
+	if err := CheckTransactionType(t.Type); err != nil {
+		return err
+	}
+	if t.ChangeAmount == 0 {
+		return fmt.Errorf("change_amount must not be zero")
+	}
+	switch t.Type {
+	case models.TransactionPurchase:
+		if t.ChangeAmount < 0 {
+			return fmt.Errorf("purchase must have a positive change_amount, got: %v", t.ChangeAmount)
+		}
+	case models.TransactionUse, models.TransactionWaste:
+		if t.ChangeAmount > 0 {
+			return fmt.Errorf("%s must have a negative change_amount, got: %v", t.Type, t.ChangeAmount)
+		}
+	}
+	if t.ExpiresAt != nil {
+		if t.ChangeAmount < 0 {
+			return fmt.Errorf("expires_at is only allowed for incoming stock")
+		}
+		if err := CheckExpiryDate(t.ExpiresAt); err != nil {
+			return err
+		}
+	}
+	if t.UnitCost != nil {
+		if t.ChangeAmount < 0 {
+			return fmt.Errorf("unit_cost is only allowed for incoming stock")
+		}
+		if *t.UnitCost < 0 {
+			return fmt.Errorf("unit_cost must not be negative, got: %v", *t.UnitCost)
+		}
+	}
+	if t.Reason != "" {
+		if t.Type != models.TransactionWaste {
+			return fmt.Errorf("reason is only allowed for waste")
+		}
+		return CheckWasteReason(t.Reason)
+	}
+	return nil
+}
+
+func CheckWasteReason(reason string) error {
+	switch reason {
+	case models.WasteExpired, models.WasteSpilled, models.WasteQuality:
+		return nil
+	}
+	return fmt.Errorf("waste reason must be one of expired, spilled, quality, got: %q", reason)
+}
+
+// CheckExpiryDate проверяет срок годности партии в формате YYYY-MM-DD; nil — без срока.
+func CheckExpiryDate(date *string) error {
+	if date == nil {
+		return nil
+	}
+	if _, err := time.Parse(time.DateOnly, *date); err != nil {
+		return fmt.Errorf("invalid expires_at %q, expected YYYY-MM-DD", *date)
+	}
 	return nil
 }
@@ -0,0 +1,132 @@
+package helper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"frappuccino/internal/models"
+)
+
+// InventoryCSVColumns — колонки выгрузки склада. При импорте вместо
+// reorder_threshold можно писать threshold.
+var InventoryCSVColumns = []string{"id", "name", "stock", "unit", "reorder_threshold", "price"}
+
+func WriteInventoryCSV(w io.Writer, items []models.InventoryExportItem) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(InventoryCSVColumns); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		record := []string{
+			strconv.Itoa(item.ID),
+			item.Name,
+			formatFloat(item.Stock),
+			item.Unit,
+			formatOptional(item.ReorderThreshold),
+			formatOptional(item.Price),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadInventoryCSV читает выгрузку склада так же, как ReadMenuCSV: колонки
+// ищутся по заголовку, обязательна только name, ошибки разбора строк
+// возвращаются в rowErrors по индексам items.
+func ReadInventoryCSV(r io.Reader) (items []models.InventoryExportItem, rowErrors []error, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, fmt.Errorf("CSV file is empty")
+		}
+		return nil, nil, fmt.Errorf("failed to read CSV header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	// Excel сохраняет CSV с BOM в начале первой колонки
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, nil, fmt.Errorf("CSV header must contain a name column")
+	}
+	if i, ok := columns["threshold"]; ok {
+		if _, exists := columns["reorder_threshold"]; !exists {
+			columns["reorder_threshold"] = i
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %v", err)
+		}
+
+		item, rowErr := parseInventoryRecord(record, columns)
+		items = append(items, item)
+		rowErrors = append(rowErrors, rowErr)
+	}
+	return items, rowErrors, nil
+}
+
+func parseInventoryRecord(record []string, columns map[string]int) (models.InventoryExportItem, error) {
+	get := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var item models.InventoryExportItem
+	item.Name = get("name")
+	item.Unit = get("unit")
+
+	var err error
+	if v := get("id"); v != "" {
+		if item.ID, err = strconv.Atoi(v); err != nil || item.ID <= 0 {
+			return item, fmt.Errorf("invalid id: %q", v)
+		}
+	}
+	if v := get("stock"); v != "" {
+		if item.Stock, err = strconv.ParseFloat(v, 64); err != nil {
+			return item, fmt.Errorf("invalid stock: %q", v)
+		}
+	}
+	for _, f := range []struct {
+		column string
+		value  **float64
+	}{{"reorder_threshold", &item.ReorderThreshold}, {"price", &item.Price}} {
+		v := get(f.column)
+		if v == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return item, fmt.Errorf("invalid %s: %q", f.column, v)
+		}
+		*f.value = &parsed
+	}
+	return item, nil
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
@@ -0,0 +1,93 @@
+package helper
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"frappuccino/internal/models"
+)
+
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale приводит тег языка к виду "pt-br": нижний регистр, дефис вместо подчёркивания.
+func NormalizeLocale(value string) (string, error) {
+	locale := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), "_", "-"))
+	if !localeRegex.MatchString(locale) {
+		return "", fmt.Errorf("invalid locale %q: expected a language tag like \"de\" or \"pt-BR\"", value)
+	}
+	return locale, nil
+}
+
+// ParseAcceptLanguage возвращает языки из заголовка Accept-Language по убыванию q.
+// Некорректные теги и "*" пропускаются.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var langs []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale, err := NormalizeLocale(tag)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		langs = append(langs, weighted{locale: locale, q: q})
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	result := make([]string, 0, len(langs))
+	for _, l := range langs {
+		result = append(result, l.locale)
+	}
+	return result
+}
+
+// Localize подставляет перевод по первому подходящему языку из languages.
+// "de-at" без своего перевода берёт "de"; если ничего не нашлось, остаётся язык по умолчанию.
+func Localize(item *models.MenuItem, translations []models.MenuItemTranslation, languages []string) {
+	item.Locale = models.DefaultLocale
+
+	byLocale := make(map[string]models.MenuItemTranslation, len(translations))
+	for _, t := range translations {
+		byLocale[t.Locale] = t
+	}
+
+	for _, lang := range languages {
+		base, _, _ := strings.Cut(lang, "-")
+		if lang == models.DefaultLocale || base == models.DefaultLocale {
+			return
+		}
+		t, ok := byLocale[lang]
+		if !ok {
+			t, ok = byLocale[base]
+		}
+		if !ok {
+			continue
+		}
+
+		item.Name = t.Name
+		item.Locale = t.Locale
+		if t.Description != "" {
+			item.Description = t.Description
+		}
+		if len(t.Allergens) > 0 {
+			item.Allergens = t.Allergens
+		}
+		return
+	}
+}
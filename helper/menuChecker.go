@@ -19,12 +19,16 @@ func CheckerForMenuItems(item models.MenuItem, allInventory []models.InventoryIt
 		if i.IngredientID == 0 {
 			return fmt.Errorf("ingredient ID should not be empty")
 		}
-		if _, exists := inventoryMap[i.IngredientID]; !exists {
+		inv, exists := inventoryMap[i.IngredientID]
+		if !exists {
 			return fmt.Errorf("ingredient with ID %d not found in inventory", i.IngredientID)
 		}
 		if i.Quantity <= 0 {
 			return fmt.Errorf("ingredient quantity should be greater than 0, got: %f", i.Quantity)
 		}
+		if _, err := RecipeQuantity(i, inv); err != nil {
+			return fmt.Errorf("ingredient with ID %d: %w", i.IngredientID, err)
+		}
 	}
 	return nil
 }
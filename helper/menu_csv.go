@@ -0,0 +1,210 @@
+package helper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"frappuccino/internal/models"
+)
+
+// MenuCSVColumns — колонки выгрузки меню. Списки разделяются «;», ингредиенты
+// записываются как id:количество:единица, компоненты набора — как id:количество.
+var MenuCSVColumns = []string{
+	"id", "type", "name", "description", "size", "price", "available",
+	"categories", "allergens", "ingredients", "components",
+}
+
+func WriteMenuCSV(w io.Writer, items []models.MenuExportItem) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(MenuCSVColumns); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		ingredients := make([]string, 0, len(item.Ingredients))
+		for _, ing := range item.Ingredients {
+			ingredients = append(ingredients, fmt.Sprintf("%d:%s:%s", ing.IngredientID, formatFloat(ing.Quantity), ing.Unit))
+		}
+		components := make([]string, 0, len(item.Components))
+		for _, c := range item.Components {
+			components = append(components, fmt.Sprintf("%d:%d", c.MenuItemID, c.Quantity))
+		}
+		available := true
+		if item.Available != nil {
+			available = *item.Available
+		}
+
+		record := []string{
+			strconv.Itoa(item.ID),
+			item.Type,
+			item.Name,
+			formatString(item.Description),
+			formatString(item.Size),
+			formatOptional(item.Price),
+			strconv.FormatBool(available),
+			strings.Join(item.Categories, ";"),
+			strings.Join(item.Allergens, ";"),
+			strings.Join(ingredients, ";"),
+			strings.Join(components, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadMenuCSV читает выгрузку меню. Колонки ищутся по заголовку, лишние
+// колонки таблицы пропускаются, обязательна только name. Ошибки разбора
+// строк возвращаются в rowErrors по тем же индексам, что и items, чтобы
+// одна кривая строка не отменяла разбор всего файла.
+func ReadMenuCSV(r io.Reader) (items []models.MenuExportItem, rowErrors []error, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, fmt.Errorf("CSV file is empty")
+		}
+		return nil, nil, fmt.Errorf("failed to read CSV header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	// Excel сохраняет CSV с BOM в начале первой колонки
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, nil, fmt.Errorf("CSV header must contain a name column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %v", err)
+		}
+
+		item, rowErr := parseMenuRecord(record, columns)
+		items = append(items, item)
+		rowErrors = append(rowErrors, rowErr)
+	}
+	return items, rowErrors, nil
+}
+
+func parseMenuRecord(record []string, columns map[string]int) (models.MenuExportItem, error) {
+	get := func(name string) (string, bool) {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return "", ok
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+
+	var item models.MenuExportItem
+	item.Name, _ = get("name")
+	item.Type, _ = get("type")
+	// Пустая колонка очищает значение, а отсутствующая оставляет его как есть
+	if v, ok := get("description"); ok {
+		item.Description = &v
+	}
+	if v, ok := get("size"); ok {
+		item.Size = &v
+	}
+	if v, ok := get("categories"); ok {
+		item.Categories = append([]string{}, splitList(v)...)
+	}
+	if v, ok := get("allergens"); ok {
+		item.Allergens = append([]string{}, splitList(v)...)
+	}
+
+	var err error
+	if v, _ := get("id"); v != "" {
+		if item.ID, err = strconv.Atoi(v); err != nil || item.ID <= 0 {
+			return item, fmt.Errorf("invalid id: %q", v)
+		}
+	}
+	if v, _ := get("price"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return item, fmt.Errorf("invalid price: %q", v)
+		}
+		item.Price = &price
+	}
+	if v, _ := get("available"); v != "" {
+		available, err := strconv.ParseBool(v)
+		if err != nil {
+			return item, fmt.Errorf("invalid available: %q", v)
+		}
+		item.Available = &available
+	}
+
+	// Пустая колонка очищает рецептуру, а отсутствующая оставляет её как есть
+	if v, ok := get("ingredients"); ok {
+		item.Ingredients = []models.MenuItemIngredient{}
+		for _, part := range splitList(v) {
+			fields := strings.Split(part, ":")
+			if len(fields) != 3 {
+				return item, fmt.Errorf("invalid ingredient %q, expected id:quantity:unit", part)
+			}
+			id, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+			if err != nil {
+				return item, fmt.Errorf("invalid ingredient id in %q", part)
+			}
+			quantity, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+			if err != nil {
+				return item, fmt.Errorf("invalid ingredient quantity in %q", part)
+			}
+			item.Ingredients = append(item.Ingredients, models.MenuItemIngredient{
+				IngredientID: id, Quantity: quantity, Unit: strings.TrimSpace(fields[2]),
+			})
+		}
+	}
+	if v, ok := get("components"); ok && v != "" {
+		for _, part := range splitList(v) {
+			fields := strings.Split(part, ":")
+			if len(fields) != 2 {
+				return item, fmt.Errorf("invalid component %q, expected menu_item_id:quantity", part)
+			}
+			id, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+			if err != nil {
+				return item, fmt.Errorf("invalid component id in %q", part)
+			}
+			quantity, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+			if err != nil {
+				return item, fmt.Errorf("invalid component quantity in %q", part)
+			}
+			item.Components = append(item.Components, models.BundleComponent{MenuItemID: id, Quantity: quantity})
+		}
+	}
+	return item, nil
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, part := range strings.Split(value, ";") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,77 @@
+package helper
+
+import (
+	"reflect"
+
+	"frappuccino/internal/models"
+)
+
+// DiffMenus сравнивает два снимка меню по id позиций.
+func DiffMenus(from, to []models.MenuItem) (added, removed []models.MenuItem, changed []models.MenuItemDiff) {
+	before := make(map[int]models.MenuItem, len(from))
+	for _, item := range from {
+		before[item.ID] = item
+	}
+	after := make(map[int]bool, len(to))
+
+	added, removed, changed = []models.MenuItem{}, []models.MenuItem{}, []models.MenuItemDiff{}
+	for _, item := range to {
+		after[item.ID] = true
+		old, exists := before[item.ID]
+		if !exists {
+			added = append(added, item)
+			continue
+		}
+
+		if changes := DiffMenuItem(old, item); len(changes) > 0 {
+			changed = append(changed, models.MenuItemDiff{MenuItemID: item.ID, Name: item.Name, Changes: changes})
+		}
+	}
+
+	for _, item := range from {
+		if !after[item.ID] {
+			removed = append(removed, item)
+		}
+	}
+	return added, removed, changed
+}
+
+// DiffMenuItem возвращает изменившиеся редактируемые поля позиции.
+func DiffMenuItem(old, item models.MenuItem) map[string]models.FieldChange {
+	oldFields, newFields := diffFields(old), diffFields(item)
+	changes := make(map[string]models.FieldChange)
+	for name, value := range newFields {
+		if !reflect.DeepEqual(oldFields[name], value) {
+			changes[name] = models.FieldChange{Old: oldFields[name], New: value}
+		}
+	}
+	return changes
+}
+
+// diffFields — редактируемые поля позиции; пустые списки приводятся к nil,
+// чтобы [] и null не считались изменением.
+func diffFields(item models.MenuItem) map[string]any {
+	// Имя компонента подтягивается из его позиции и к самому набору не относится
+	components := make([]models.BundleComponent, 0, len(item.Components))
+	for _, c := range item.Components {
+		components = append(components, models.BundleComponent{MenuItemID: c.MenuItemID, Quantity: c.Quantity})
+	}
+
+	fields := map[string]any{
+		"name":         item.Name,
+		"description":  item.Description,
+		"category_ids": item.CategoryIDs,
+		"allergens":    item.Allergens,
+		"price":        item.Price,
+		"available":    item.Available,
+		"size":         item.Size,
+		"ingredients":  item.Ingredients,
+		"components":   components,
+	}
+	for name, value := range fields {
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Slice && v.Len() == 0 {
+			fields[name] = nil
+		}
+	}
+	return fields
+}
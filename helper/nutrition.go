@@ -0,0 +1,78 @@
+package helper
+
+import (
+	"fmt"
+	"math"
+
+	"frappuccino/internal/models"
+)
+
+// ComputeNutrition суммирует пищевую ценность ингредиентов рецептуры на одну порцию.
+// Для позиций без рецептуры возвращает nil.
+func ComputeNutrition(ingredients []models.MenuItemIngredient, inventory map[int]models.InventoryItem) *models.MenuItemNutrition {
+	if len(ingredients) == 0 {
+		return nil
+	}
+
+	result := &models.MenuItemNutrition{Complete: true}
+	for _, ing := range ingredients {
+		inv, exists := inventory[ing.IngredientID]
+		if !exists || inv.Nutrition == nil {
+			result.Complete = false
+			continue
+		}
+		n := inv.Nutrition
+		result.Calories += n.Calories * ing.Quantity
+		result.Fat += n.Fat * ing.Quantity
+		result.Sugar += n.Sugar * ing.Quantity
+		result.Protein += n.Protein * ing.Quantity
+		result.Caffeine += n.Caffeine * ing.Quantity
+	}
+
+	result.Calories = math.Round(result.Calories)
+	result.Fat = round1(result.Fat)
+	result.Sugar = round1(result.Sugar)
+	result.Protein = round1(result.Protein)
+	result.Caffeine = math.Round(result.Caffeine)
+	return result
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// MatchesNutrition проверяет ограничения фильтра. Позиции без данных или с неполными
+// данными под ограничения не попадают: заявить «до 200 ккал» для них нельзя.
+func MatchesNutrition(n *models.MenuItemNutrition, filter models.MenuFilter) bool {
+	if filter.MaxCalories == nil && filter.MaxSugar == nil && filter.MaxCaffeine == nil {
+		return true
+	}
+	if n == nil || !n.Complete {
+		return false
+	}
+	if filter.MaxCalories != nil && n.Calories > *filter.MaxCalories {
+		return false
+	}
+	if filter.MaxSugar != nil && n.Sugar > *filter.MaxSugar {
+		return false
+	}
+	if filter.MaxCaffeine != nil && n.Caffeine > *filter.MaxCaffeine {
+		return false
+	}
+	return true
+}
+
+func CheckNutrition(n *models.Nutrition) error {
+	if n == nil {
+		return nil
+	}
+	values := map[string]float64{
+		"calories": n.Calories, "fat": n.Fat, "sugar": n.Sugar, "protein": n.Protein, "caffeine": n.Caffeine,
+	}
+	for name, v := range values {
+		if v < 0 {
+			return fmt.Errorf("nutrition %s must not be negative, got: %v", name, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"math"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/units"
+)
+
+// ReorderQuantity считает, сколько заказать. Заказ нужен, когда остаток вместе
+// с уже заказанным не переживёт срок поставки, не опустившись ниже порога;
+// тогда заказывается расход на срок поставки и горизонт плюс порог как страховой
+// запас. Штучные товары округляются вверх до целых.
+func ReorderQuantity(item models.InventoryItem, dailyUsage, onOrder float64, leadTimeDays, horizonDays int) float64 {
+	available := item.Stock + onOrder
+	reorderPoint := item.ReorderThreshold + dailyUsage*float64(leadTimeDays)
+	if available > reorderPoint {
+		return 0
+	}
+
+	target := item.ReorderThreshold + dailyUsage*float64(leadTimeDays+horizonDays)
+	quantity := target - available
+	if quantity <= 0 {
+		return 0
+	}
+
+	if unit, err := units.Parse(item.Unit); err == nil && unit.Dimension == units.Count {
+		return math.Ceil(quantity)
+	}
+	return math.Ceil(quantity*10000) / 10000
+}
@@ -0,0 +1,146 @@
+package helper
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"frappuccino/internal/models"
+)
+
+// ParseClock переводит время вида "HH:MM" в минуты от полуночи.
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func CheckTimeWindow(days []int, start, end string) error {
+	for _, d := range days {
+		if d < 0 || d > 6 {
+			return fmt.Errorf("day of week must be between 0 (Sunday) and 6 (Saturday), got: %d", d)
+		}
+	}
+	startMin, err := ParseClock(start)
+	if err != nil {
+		return err
+	}
+	endMin, err := ParseClock(end)
+	if err != nil {
+		return err
+	}
+	if startMin == endMin {
+		return fmt.Errorf("start time and end time must differ")
+	}
+	return nil
+}
+
+// InTimeWindow проверяет, попадает ли t в окно. Окна через полночь (22:00–02:00)
+// относятся к дню, в который начались.
+func InTimeWindow(days []int, start, end string, t time.Time) bool {
+	startMin, err := ParseClock(start)
+	if err != nil {
+		return false
+	}
+	endMin, err := ParseClock(end)
+	if err != nil {
+		return false
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	day := int(t.Weekday())
+
+	if startMin < endMin {
+		return now >= startMin && now < endMin && dayAllowed(days, day)
+	}
+
+	if now >= startMin {
+		return dayAllowed(days, day)
+	}
+	if now < endMin {
+		return dayAllowed(days, (day+6)%7)
+	}
+	return false
+}
+
+func dayAllowed(days []int, day int) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAvailableAt учитывает ручной флаг available, распродажу по остаткам и окна доступности позиции.
+func IsAvailableAt(item models.MenuItem, t time.Time) bool {
+	if !item.Available || item.SoldOut {
+		return false
+	}
+	if len(item.AvailabilityWindows) == 0 {
+		return true
+	}
+	for _, w := range item.AvailabilityWindows {
+		if InTimeWindow(w.DaysOfWeek, w.StartTime, w.EndTime, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// PriceAt возвращает цену позиции с учётом самого выгодного правила, действующего в момент t.
+func PriceAt(item models.MenuItem, rules []models.PriceRule, t time.Time) (float64, *models.PriceRule) {
+	var best *models.PriceRule
+	for i := range rules {
+		rule := rules[i]
+		if !rule.Active || !ruleMatchesItem(rule, item) {
+			continue
+		}
+		if !InTimeWindow(rule.DaysOfWeek, rule.StartTime, rule.EndTime, t) {
+			continue
+		}
+		if best == nil || rule.DiscountPercent > best.DiscountPercent {
+			best = &rules[i]
+		}
+	}
+
+	if best == nil {
+		return item.Price, nil
+	}
+	price := item.Price * (1 - best.DiscountPercent/100)
+	return math.Round(price*100) / 100, best
+}
+
+func ruleMatchesItem(rule models.PriceRule, item models.MenuItem) bool {
+	if rule.MenuItemID != nil && *rule.MenuItemID != item.ID {
+		return false
+	}
+	if rule.Category == "" {
+		return true
+	}
+	for _, c := range item.Categories {
+		if strings.EqualFold(c, rule.Category) {
+			return true
+		}
+	}
+	return false
+}
+
+func CheckPriceRule(rule models.PriceRule) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("please provide a name for the price rule")
+	}
+	if rule.DiscountPercent <= 0 || rule.DiscountPercent > 100 {
+		return fmt.Errorf("discount percent must be greater than 0 and at most 100, got: %v", rule.DiscountPercent)
+	}
+	if rule.MenuItemID == nil && rule.Category == "" {
+		return fmt.Errorf("price rule must target a menu item or a category")
+	}
+	return CheckTimeWindow(rule.DaysOfWeek, rule.StartTime, rule.EndTime)
+}
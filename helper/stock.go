@@ -0,0 +1,46 @@
+package helper
+
+import (
+	"math"
+
+	"frappuccino/internal/models"
+)
+
+// PortionsAvailable считает, сколько порций позиции можно приготовить из текущих остатков.
+// Для позиций без рецептуры возвращает nil — их количество не ограничено складом.
+// Остатки замен прибавляются к остатку ингредиента в пересчёте по ratio; если одна
+// замена общая для нескольких ингредиентов, оценка получается оптимистичной, и
+// окончательно остатки проверяются при списании.
+func PortionsAvailable(ingredients []models.MenuItemIngredient, inventory map[int]models.InventoryItem,
+	substitutes map[int][]models.IngredientSubstitute) *int {
+	if len(ingredients) == 0 {
+		return nil
+	}
+
+	portions := math.MaxInt
+	for _, ing := range ingredients {
+		if ing.Quantity <= 0 {
+			continue
+		}
+		stock := 0.0
+		if inv, exists := inventory[ing.IngredientID]; exists {
+			stock = inv.Stock
+		}
+		for _, sub := range substitutes[ing.IngredientID] {
+			if inv, exists := inventory[sub.SubstituteID]; exists && sub.Ratio > 0 {
+				stock += inv.Stock / sub.Ratio
+			}
+		}
+		if n := int(math.Floor(stock / ing.Quantity)); n < portions {
+			portions = n
+		}
+	}
+
+	if portions == math.MaxInt {
+		return nil
+	}
+	if portions < 0 {
+		portions = 0
+	}
+	return &portions
+}
@@ -0,0 +1,57 @@
+package helper
+
+import (
+	"math"
+
+	"frappuccino/internal/models"
+)
+
+// Substitute раскладывает списания по остаткам. Если исходного ингредиента не
+// хватает на строку списания, она целиком переходит на первую по приоритету замену,
+// которой хватает: полпорции молока и полпорции овсяного никто не заказывал.
+// Строки, которые закрыть не удалось, остаются как есть — их отклонит проверка остатков.
+func Substitute(usage []models.IngredientUsage, inventory map[int]models.InventoryItem,
+	substitutes map[int][]models.IngredientSubstitute) ([]models.IngredientUsage, map[int][]models.OrderItemSubstitution) {
+	stock := make(map[int]float64, len(inventory))
+	for id, inv := range inventory {
+		stock[id] = inv.Stock
+	}
+
+	result := make([]models.IngredientUsage, 0, len(usage))
+	made := make(map[int][]models.OrderItemSubstitution)
+	for _, u := range usage {
+		if stock[u.IngredientID] >= u.Quantity {
+			stock[u.IngredientID] -= u.Quantity
+			result = append(result, u)
+			continue
+		}
+
+		substituted := false
+		for _, sub := range substitutes[u.IngredientID] {
+			quantity := math.Round(u.Quantity*sub.Ratio*10000) / 10000
+			if quantity <= 0 || stock[sub.SubstituteID] < quantity {
+				continue
+			}
+			stock[sub.SubstituteID] -= quantity
+			result = append(result, models.IngredientUsage{
+				ItemIndex:    u.ItemIndex,
+				IngredientID: sub.SubstituteID,
+				Quantity:     quantity,
+			})
+			made[u.ItemIndex] = append(made[u.ItemIndex], models.OrderItemSubstitution{
+				IngredientID:     u.IngredientID,
+				IngredientName:   inventory[u.IngredientID].Name,
+				SubstituteID:     sub.SubstituteID,
+				SubstituteName:   inventory[sub.SubstituteID].Name,
+				OriginalQuantity: u.Quantity,
+				Quantity:         quantity,
+			})
+			substituted = true
+			break
+		}
+		if !substituted {
+			result = append(result, u)
+		}
+	}
+	return result, made
+}
@@ -0,0 +1,49 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/units"
+)
+
+// RecipeQuantity переводит количество ингредиента из единиц рецептуры в единицы склада.
+// Пустая единица в рецептуре означает единицу склада.
+func RecipeQuantity(ing models.MenuItemIngredient, inv models.InventoryItem) (float64, error) {
+	if ing.Unit == "" || strings.EqualFold(strings.TrimSpace(ing.Unit), strings.TrimSpace(inv.Unit)) {
+		return ing.Quantity, nil
+	}
+	quantity, err := units.Convert(ing.Quantity, ing.Unit, inv.Unit)
+	if err != nil {
+		return 0, fmt.Errorf("recipe unit %q cannot be used for %s stocked in %q: %w", ing.Unit, inv.Name, inv.Unit, err)
+	}
+	return quantity, nil
+}
+
+// ConvertRecipes приводит количества в рецептурах к единицам склада, чтобы
+// остатки, списания и пищевая ценность считались в одних единицах. Строки,
+// которые перевести нельзя (старые данные с несовместимыми единицами),
+// остаются как есть и возвращаются в problems.
+func ConvertRecipes(recipes map[int][]models.MenuItemIngredient, inventory map[int]models.InventoryItem) (converted map[int][]models.MenuItemIngredient, problems []error) {
+	converted = make(map[int][]models.MenuItemIngredient, len(recipes))
+	for menuItemID, ingredients := range recipes {
+		result := make([]models.MenuItemIngredient, 0, len(ingredients))
+		for _, ing := range ingredients {
+			inv, exists := inventory[ing.IngredientID]
+			if !exists {
+				result = append(result, ing)
+				continue
+			}
+			quantity, err := RecipeQuantity(ing, inv)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("menu item %d: %w", menuItemID, err))
+				result = append(result, ing)
+				continue
+			}
+			result = append(result, models.MenuItemIngredient{IngredientID: ing.IngredientID, Quantity: quantity, Unit: inv.Unit})
+		}
+		converted[menuItemID] = result
+	}
+	return converted, problems
+}
@@ -0,0 +1,92 @@
+package helper
+
+import (
+	"time"
+
+	"frappuccino/internal/models"
+)
+
+// LedgerCost — результат прогона журнала ингредиента. Costs[i] — себестоимость
+// расхода в записи ledger[i], у поступлений 0.
+type LedgerCost struct {
+	Stock float64
+	Value float64
+	Costs []float64
+}
+
+type costLayer struct {
+	quantity float64
+	unitCost float64
+}
+
+// CostLedger прогоняет журнал одного ингредиента в хронологическом порядке и
+// оценивает остаток и каждый расход методом FIFO (расход забирает самые ранние
+// поступления по их цене) или по скользящей средневзвешенной цене. Поступления
+// без unit_cost (корректировки, возвраты, старые записи) оцениваются по price.
+// Перемещения между локациями пропускаются: товар в пути остаётся на балансе
+// и сохраняет свою цену.
+func CostLedger(method string, ledger []models.InventoryTransaction, price float64) LedgerCost {
+	result := LedgerCost{Costs: make([]float64, len(ledger))}
+	var layers []costLayer
+	var average float64
+
+	for i, t := range ledger {
+		if t.TransferID != nil {
+			continue
+		}
+		if t.ChangeAmount > 0 {
+			unitCost := price
+			if t.UnitCost != nil {
+				unitCost = *t.UnitCost
+			}
+			if result.Stock+t.ChangeAmount > 0 {
+				average = (result.Stock*average + t.ChangeAmount*unitCost) / (result.Stock + t.ChangeAmount)
+			}
+			layers = append(layers, costLayer{t.ChangeAmount, unitCost})
+			result.Stock += t.ChangeAmount
+			continue
+		}
+
+		quantity := -t.ChangeAmount
+		result.Stock -= quantity
+		if method == models.ValuationWAC {
+			result.Costs[i] = quantity * average
+			continue
+		}
+		for quantity > 0 && len(layers) > 0 {
+			take := min(quantity, layers[0].quantity)
+			result.Costs[i] += take * layers[0].unitCost
+			layers[0].quantity -= take
+			quantity -= take
+			if layers[0].quantity <= 0 {
+				layers = layers[1:]
+			}
+		}
+		// Журнал без остатка под расход — такого быть не должно, считаем по цене
+		result.Costs[i] += quantity * price
+	}
+
+	if method == models.ValuationWAC {
+		result.Value = result.Stock * average
+		return result
+	}
+	for _, layer := range layers {
+		result.Value += layer.quantity * layer.unitCost
+	}
+	return result
+}
+
+// PeriodKey возвращает метку периода: день (YYYY-MM-DD), понедельник недели
+// (YYYY-MM-DD) или месяц (YYYY-MM).
+func PeriodKey(t time.Time, period string) string {
+	switch period {
+	case "day":
+		return t.Format(time.DateOnly)
+	case "week":
+		offset := (int(t.Weekday()) + 6) % 7
+		return t.AddDate(0, 0, -offset).Format(time.DateOnly)
+	case "month":
+		return t.Format("2006-01")
+	}
+	return ""
+}
@@ -1,10 +1,19 @@
 -- ENUM Types
-CREATE TYPE order_status AS ENUM ('pending', 'preparing', 'ready', 'delivered', 'cancelled', 'closed');
+CREATE TYPE order_status AS ENUM ('open', 'pending', 'preparing', 'ready', 'delivered', 'cancelled', 'closed');
 CREATE TYPE payment_method AS ENUM ('cash', 'card', 'online');
 CREATE TYPE item_size AS ENUM ('small', 'medium', 'large');
 CREATE TYPE staff_role AS ENUM ('admin', 'chef', 'waiter', 'cashier');
+CREATE TYPE purchase_order_status AS ENUM ('draft', 'sent', 'partially_received', 'received');
 
 -- Tables
+-- Cafés and the central bakery; every lot, ledger row and order belongs to one.
+-- Location 1 is the main café and the default everywhere.
+CREATE TABLE locations (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    address TEXT NOT NULL DEFAULT ''
+);
+
 CREATE TABLE customers (
     id SERIAL PRIMARY KEY,
     name TEXT NOT NULL,
@@ -18,21 +27,87 @@ CREATE TABLE orders (
     total_amount DECIMAL(10,2) NOT NULL CHECK (total_amount >= 0),
     payment_method payment_method NOT NULL,
     special_instructions JSONB DEFAULT '{}'::JSONB,
+    location_id INT NOT NULL DEFAULT 1 REFERENCES locations(id) ON DELETE RESTRICT,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
 );
 
 CREATE TABLE menu_items (
     id SERIAL PRIMARY KEY,
+    item_type TEXT NOT NULL DEFAULT 'single' CHECK (item_type IN ('single', 'bundle')),
     name TEXT NOT NULL,
     description TEXT,
     categories TEXT[] DEFAULT '{}',
     allergens TEXT[] DEFAULT '{}',
     price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
     available BOOLEAN DEFAULT TRUE,
+    sold_out BOOLEAN NOT NULL DEFAULT FALSE,
     size item_size NOT NULL
 );
 
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    display_order INT NOT NULL DEFAULT 0,
+    parent_id INT REFERENCES categories(id) ON DELETE SET NULL,
+    CHECK (parent_id <> id)
+);
+
+CREATE TABLE menu_item_categories (
+    menu_item_id INT NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (menu_item_id, category_id)
+);
+
+-- Translations of customer-facing texts; menu_items keeps the default language (en).
+CREATE TABLE menu_item_translations (
+    menu_item_id INT NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    allergens TEXT[] DEFAULT '{}',
+    PRIMARY KEY (menu_item_id, locale)
+);
+
+-- Image files live in media storage; the table keeps their storage names.
+CREATE TABLE menu_item_images (
+    id SERIAL PRIMARY KEY,
+    menu_item_id INT NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    file_name TEXT NOT NULL,
+    thumbnail_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TABLE bundle_components (
+    bundle_id INT NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    component_id INT NOT NULL REFERENCES menu_items(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    PRIMARY KEY (bundle_id, component_id),
+    CHECK (bundle_id <> component_id)
+);
+
+CREATE TABLE menu_item_availability (
+    id SERIAL PRIMARY KEY,
+    menu_item_id INT NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    days_of_week INT[] DEFAULT '{}',
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL CHECK (end_time <> start_time)
+);
+
+CREATE TABLE price_rules (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    menu_item_id INT REFERENCES menu_items(id) ON DELETE CASCADE,
+    category TEXT NOT NULL DEFAULT '',
+    discount_percent DECIMAL(5,2) NOT NULL CHECK (discount_percent > 0 AND discount_percent <= 100),
+    days_of_week INT[] DEFAULT '{}',
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL CHECK (end_time <> start_time),
+    active BOOLEAN DEFAULT TRUE
+);
+
 CREATE TABLE order_items (
     id SERIAL PRIMARY KEY,
     order_id INT REFERENCES orders(id) ON DELETE CASCADE,
@@ -45,12 +120,36 @@ CREATE TABLE order_items (
 CREATE TABLE inventory (
     id SERIAL PRIMARY KEY,
     name TEXT NOT NULL UNIQUE,
-    stock DECIMAL(10,2) NOT NULL CHECK (stock >= 0),
+    -- Recipes are converted into this unit on deduction, so 5 g from a stock
+    -- kept in kg has to fit: hence four decimal places.
+    stock DECIMAL(12,4) NOT NULL CHECK (stock >= 0),
     unit TEXT NOT NULL,
     reorder_threshold DECIMAL(10,2) NOT NULL CHECK (reorder_threshold >= 0),
     price NUMERIC(10, 2) NOT NULL CHECK (price >= 0)
 );
 
+-- Nutrition per one unit of the ingredient's unit (per g, ml, pcs or kg).
+CREATE TABLE inventory_nutrition (
+    ingredient_id INT PRIMARY KEY REFERENCES inventory(id) ON DELETE CASCADE,
+    calories DECIMAL(10,4) NOT NULL DEFAULT 0 CHECK (calories >= 0),
+    fat DECIMAL(10,4) NOT NULL DEFAULT 0 CHECK (fat >= 0),
+    sugar DECIMAL(10,4) NOT NULL DEFAULT 0 CHECK (sugar >= 0),
+    protein DECIMAL(10,4) NOT NULL DEFAULT 0 CHECK (protein >= 0),
+    caffeine DECIMAL(10,4) NOT NULL DEFAULT 0 CHECK (caffeine >= 0)
+);
+
+-- Substitutes used automatically when an ingredient runs short: one unit of
+-- ingredient_id is replaced by ratio units of substitute_id (in the substitute's unit).
+CREATE TABLE ingredient_substitutes (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INT NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    substitute_id INT NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    ratio DECIMAL(10,4) NOT NULL CHECK (ratio > 0),
+    priority INT NOT NULL DEFAULT 0,
+    UNIQUE (ingredient_id, substitute_id),
+    CHECK (ingredient_id <> substitute_id)
+);
+
 CREATE TABLE menu_item_ingredients (
     id SERIAL PRIMARY KEY,
     menu_item_id INT REFERENCES menu_items(id) ON DELETE CASCADE,
@@ -59,6 +158,15 @@ CREATE TABLE menu_item_ingredients (
     unit TEXT NOT NULL
 );
 
+CREATE TABLE order_item_substitutions (
+    id SERIAL PRIMARY KEY,
+    order_item_id INT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    ingredient_id INT REFERENCES inventory(id) ON DELETE SET NULL,
+    substitute_id INT REFERENCES inventory(id) ON DELETE SET NULL,
+    original_quantity DECIMAL(12,4) NOT NULL CHECK (original_quantity > 0),
+    quantity DECIMAL(12,4) NOT NULL CHECK (quantity > 0)
+);
+
 CREATE TABLE order_status_history (
     id SERIAL PRIMARY KEY,
     order_id INT REFERENCES orders(id) ON DELETE CASCADE,
@@ -74,11 +182,142 @@ CREATE TABLE price_history (
     changed_at TIMESTAMP WITH TIME ZONE DEFAULT now()
 );
 
+CREATE TABLE scheduled_price_changes (
+    id SERIAL PRIMARY KEY,
+    menu_item_id INT NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    new_price DECIMAL(10,2) NOT NULL CHECK (new_price >= 0),
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TABLE menu_draft_changes (
+    id SERIAL PRIMARY KEY,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    menu_item_id INT REFERENCES menu_items(id) ON DELETE CASCADE,
+    payload JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    CHECK ((action = 'create') = (menu_item_id IS NULL))
+);
+
+-- Every publish stores the full menu as it went live, so versions can be diffed and restored.
+CREATE TABLE menu_versions (
+    id SERIAL PRIMARY KEY,
+    note TEXT NOT NULL DEFAULT '',
+    snapshot JSONB NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TABLE suppliers (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    contact_name TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    lead_time_days INT NOT NULL DEFAULT 0 CHECK (lead_time_days >= 0)
+);
+
+-- Price list: unit_price is per one unit of the inventory item's unit.
+CREATE TABLE supplier_items (
+    supplier_id INT NOT NULL REFERENCES suppliers(id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    unit_price DECIMAL(10,4) NOT NULL CHECK (unit_price >= 0),
+    PRIMARY KEY (supplier_id, ingredient_id)
+);
+
+CREATE TABLE purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers(id) ON DELETE RESTRICT,
+    status purchase_order_status NOT NULL DEFAULT 'draft',
+    note TEXT NOT NULL DEFAULT '',
+    -- Where the delivery is received
+    location_id INT NOT NULL DEFAULT 1 REFERENCES locations(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    expected_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE purchase_order_lines (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES inventory(id) ON DELETE RESTRICT,
+    quantity DECIMAL(12,4) NOT NULL CHECK (quantity > 0),
+    received_quantity DECIMAL(12,4) NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    unit_price DECIMAL(10,4) NOT NULL CHECK (unit_price >= 0),
+    UNIQUE (purchase_order_id, ingredient_id),
+    CHECK (received_quantity <= quantity)
+);
+
+CREATE TABLE stock_takes (
+    id SERIAL PRIMARY KEY,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'committed', 'cancelled')),
+    location_id INT NOT NULL DEFAULT 1 REFERENCES locations(id) ON DELETE RESTRICT,
+    note TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    committed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- system_quantity is the stock at the stock-take's location at the moment of counting.
+CREATE TABLE stock_take_counts (
+    stock_take_id INT NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    counted DECIMAL(12,4) NOT NULL CHECK (counted >= 0),
+    system_quantity DECIMAL(12,4) NOT NULL,
+    counted_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    PRIMARY KEY (stock_take_id, ingredient_id)
+);
+
+-- Stock moved between locations. Lines are split by the source lots so that
+-- the destination lots keep their expiry dates.
+CREATE TABLE stock_transfers (
+    id SERIAL PRIMARY KEY,
+    from_location_id INT NOT NULL REFERENCES locations(id) ON DELETE RESTRICT,
+    to_location_id INT NOT NULL REFERENCES locations(id) ON DELETE RESTRICT,
+    status TEXT NOT NULL DEFAULT 'in_transit' CHECK (status IN ('in_transit', 'received', 'cancelled')),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    received_at TIMESTAMP WITH TIME ZONE,
+    CHECK (from_location_id <> to_location_id)
+);
+
+CREATE TABLE stock_transfer_lines (
+    id SERIAL PRIMARY KEY,
+    transfer_id INT NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    ingredient_id INT NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    quantity DECIMAL(12,4) NOT NULL CHECK (quantity > 0),
+    expires_at DATE
+);
+
+-- Stock is held in lots: every receipt is a lot, consumption draws from lots
+-- FIFO by expiry, so SUM(remaining) per ingredient always equals inventory.stock
+-- (stock in transit between locations is in neither).
+CREATE TABLE inventory_lots (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INT NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    location_id INT NOT NULL DEFAULT 1 REFERENCES locations(id) ON DELETE RESTRICT,
+    quantity DECIMAL(12,4) NOT NULL CHECK (quantity > 0),
+    remaining DECIMAL(12,4) NOT NULL CHECK (remaining >= 0 AND remaining <= quantity),
+    expires_at DATE,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    purchase_order_id INT REFERENCES purchase_orders(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT ''
+);
+
 CREATE TABLE inventory_transactions (
     id SERIAL PRIMARY KEY,
     ingredient_id INT REFERENCES inventory(id) ON DELETE CASCADE,
-    change_amount DECIMAL(10,2) NOT NULL,
-    transaction_type TEXT NOT NULL CHECK (transaction_type IN ('purchase', 'use')),
+    change_amount DECIMAL(12,4) NOT NULL CHECK (change_amount <> 0),
+    transaction_type TEXT NOT NULL
+        CHECK (transaction_type IN ('purchase', 'use', 'waste', 'adjustment', 'transfer', 'return')),
+    note TEXT NOT NULL DEFAULT '',
+    -- Why stock was discarded; only waste rows carry a reason.
+    reason TEXT CHECK (reason IN ('expired', 'spilled', 'quality')),
+    order_id INT REFERENCES orders(id) ON DELETE SET NULL,
+    purchase_order_id INT REFERENCES purchase_orders(id) ON DELETE SET NULL,
+    -- Cost per unit of incoming stock; rows without it are valued at inventory.price.
+    unit_cost DECIMAL(10,4) CHECK (unit_cost >= 0),
+    location_id INT NOT NULL DEFAULT 1 REFERENCES locations(id) ON DELETE RESTRICT,
+    transfer_id INT REFERENCES stock_transfers(id) ON DELETE SET NULL,
     occurred_at TIMESTAMP WITH TIME ZONE DEFAULT now()
 );
 
@@ -91,6 +330,25 @@ FOREIGN KEY (ingredient_id)
 REFERENCES inventory(id) ON DELETE CASCADE;
 
 
+-- Продажи по позициям: комбо-наборы раскладываются на компоненты, выручка набора
+-- делится пропорционально прейскурантной цене компонентов.
+CREATE VIEW order_item_sales AS
+SELECT oi.order_id, oi.menu_item_id, oi.quantity, oi.price * oi.quantity AS revenue
+FROM order_items oi
+JOIN menu_items mi ON mi.id = oi.menu_item_id
+WHERE mi.item_type = 'single'
+UNION ALL
+SELECT oi.order_id, bc.component_id AS menu_item_id, oi.quantity * bc.quantity AS quantity,
+       oi.price * oi.quantity * COALESCE(c.price * bc.quantity / NULLIF(t.list_total, 0), 1.0 / t.components) AS revenue
+FROM order_items oi
+JOIN bundle_components bc ON bc.bundle_id = oi.menu_item_id
+JOIN menu_items c ON c.id = bc.component_id
+JOIN (
+    SELECT b.bundle_id, SUM(m.price * b.quantity) AS list_total, COUNT(*) AS components
+    FROM bundle_components b
+    JOIN menu_items m ON m.id = b.component_id
+    GROUP BY b.bundle_id
+) t ON t.bundle_id = oi.menu_item_id;
 
 -- Trigger for updated_at
 CREATE FUNCTION update_timestamp() RETURNS TRIGGER AS $$
@@ -105,13 +363,41 @@ CREATE TRIGGER update_orders_timestamp
     FOR EACH ROW
     EXECUTE FUNCTION update_timestamp();
 
+CREATE TRIGGER update_purchase_orders_timestamp
+    BEFORE UPDATE ON purchase_orders
+    FOR EACH ROW
+    EXECUTE FUNCTION update_timestamp();
+
 -- Indexes
 CREATE INDEX idx_menu_items_name ON menu_items (name);
 CREATE INDEX idx_customers_name ON customers (name);
 CREATE INDEX idx_order_items_order_id ON order_items (order_id);
+CREATE INDEX idx_order_item_substitutions_item ON order_item_substitutions (order_item_id);
 CREATE INDEX idx_orders_created_at ON orders (created_at);
+CREATE INDEX idx_inventory_transactions_ingredient ON inventory_transactions (ingredient_id, occurred_at);
+-- Only one stock-take per location can be open at a time
+CREATE UNIQUE INDEX idx_stock_takes_open ON stock_takes (location_id) WHERE status = 'open';
+CREATE INDEX idx_inventory_lots_ingredient ON inventory_lots (ingredient_id, location_id, expires_at) WHERE remaining > 0;
+CREATE INDEX idx_stock_transfer_lines_transfer_id ON stock_transfer_lines (transfer_id);
+CREATE INDEX idx_inventory_transactions_waste ON inventory_transactions (occurred_at) WHERE transaction_type = 'waste';
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders (supplier_id);
+CREATE INDEX idx_purchase_order_lines_order_id ON purchase_order_lines (purchase_order_id);
+CREATE UNIQUE INDEX idx_categories_name ON categories (LOWER(name));
+CREATE INDEX idx_menu_item_categories_category_id ON menu_item_categories (category_id);
+CREATE INDEX idx_menu_item_images_menu_item_id ON menu_item_images (menu_item_id);
+CREATE INDEX idx_bundle_components_component_id ON bundle_components (component_id);
+CREATE INDEX idx_menu_item_availability_menu_item_id ON menu_item_availability (menu_item_id);
+CREATE INDEX idx_price_history_menu_item_id ON price_history (menu_item_id);
+CREATE INDEX idx_scheduled_price_changes_pending ON scheduled_price_changes (effective_at) WHERE applied_at IS NULL;
+CREATE INDEX idx_menu_draft_changes_menu_item_id ON menu_draft_changes (menu_item_id);
 
 -- Mock data
+-- Locations
+INSERT INTO locations (name, address) VALUES
+    ('Main Street Café', '12 Main Street'),
+    ('Harbour Café', '3 Quay Road'),
+    ('Central Bakery', '40 Mill Lane');
+
 -- Customers 
 INSERT INTO customers (name, preferences) VALUES
     ('Alice Smith', '{"coffee": "latte", "size": "large"}'),
@@ -147,7 +433,8 @@ INSERT INTO inventory (name, stock, unit, reorder_threshold, price) VALUES
     ('Cherries', 1000, 'kg', 500, 3.00),
     ('Pears', 1000, 'kg', 500, 1.50),
     ('Melons', 1000, 'kg', 500, 1.00),
-    ('Blueberries', 1000, 'kg', 500, 2.00);
+    ('Blueberries', 1000, 'kg', 500, 2.00),
+    ('Oat Milk', 2000, 'ml', 500, 2.80);
 
 -- Menu Items 
 INSERT INTO menu_items (name, description, categories, allergens, price, available, size) VALUES
@@ -162,6 +449,73 @@ INSERT INTO menu_items (name, description, categories, allergens, price, availab
     ('Oat Latte', 'Latte with oat milk', ARRAY['coffee', 'hot']::text[], ARRAY[]::text[], 5.50, TRUE, 'large'),
     ('Cinnamon Roll', 'Sweet roll with cinnamon', ARRAY['pastry']::text[], ARRAY['gluten']::text[], 3.50, TRUE, 'medium');
 
+-- Bundles
+INSERT INTO menu_items (item_type, name, description, categories, allergens, price, available, size) VALUES
+    ('bundle', 'Coffee Croissant Deal', 'Latte with a buttery croissant', ARRAY['deal']::text[], ARRAY['milk', 'gluten']::text[], 6.50, TRUE, 'medium');
+
+INSERT INTO bundle_components (bundle_id, component_id, quantity) VALUES
+    (11, 1, 1), -- Coffee Croissant Deal: Latte
+    (11, 5, 1); -- Coffee Croissant Deal: Croissant
+
+-- Availability Windows (weekday breakfast for pastries)
+INSERT INTO menu_item_availability (menu_item_id, days_of_week, start_time, end_time) VALUES
+    (5, ARRAY[1, 2, 3, 4, 5], '07:00', '11:00'), -- Croissant
+    (5, ARRAY[0, 6], '08:00', '12:00');          -- Croissant, weekends
+
+-- Price Rules
+INSERT INTO price_rules (name, category, discount_percent, days_of_week, start_time, end_time) VALUES
+    ('Happy hour', 'hot', 20, ARRAY[]::int[], '16:00', '18:00');
+
+-- Inventory Nutrition (per unit: kcal, g fat, g sugar, g protein, mg caffeine)
+INSERT INTO inventory_nutrition (ingredient_id, calories, fat, sugar, protein, caffeine) VALUES
+    (1, 0.2, 0, 0, 0.01, 10),           -- Coffee Beans, per g of beans brewed
+    (2, 0.64, 0.036, 0.048, 0.034, 0),  -- Milk, per ml
+    (3, 4, 0, 1, 0, 0),                 -- Sugar, per g
+    (4, 3.3, 0, 0.8, 0, 0),             -- Vanilla Syrup, per ml
+    (5, 3.64, 0.01, 0.003, 0.1, 0),     -- Flour, per g
+    (6, 7.17, 0.81, 0.001, 0.009, 0),   -- Butter, per g
+    (7, 72, 4.8, 0.2, 6.3, 0),          -- Eggs, per piece
+    (8, 5.46, 0.31, 0.48, 0.049, 0.43), -- Chocolate, per g
+    (9, 3.4, 0.36, 0.03, 0.02, 0),      -- Cream, per ml
+    (10, 2.47, 0.012, 0.022, 0.04, 0),  -- Cinnamon, per g
+    (14, 8.84, 1, 0, 0, 0),             -- Olive Oil, per ml
+    (28, 570, 3.3, 100, 7.4, 0),        -- Blueberries, per kg
+    (29, 0.46, 0.015, 0.04, 0.01, 0);   -- Oat Milk, per ml
+
+-- Suppliers
+INSERT INTO suppliers (name, contact_name, email, phone, lead_time_days) VALUES
+    ('Bean Brothers Roastery', 'Marco Rossi', 'orders@beanbrothers.example', '+1-555-0101', 3),
+    ('Valley Dairy', 'Anna Berg', 'sales@valleydairy.example', '+1-555-0102', 1),
+    ('Baker''s Pantry Wholesale', 'Tom Lee', 'tom@bakerspantry.example', '+1-555-0103', 5);
+
+INSERT INTO supplier_items (supplier_id, ingredient_id, unit_price) VALUES
+    (1, 1, 0.0095),
+    (1, 4, 0.0270),
+    (1, 8, 0.0045),
+    (2, 2, 0.0021),
+    (2, 9, 0.0030),
+    (2, 29, 0.0026),
+    (2, 6, 0.0023),
+    (3, 3, 0.0014),
+    (3, 5, 0.0007),
+    (3, 7, 0.3500),
+    (3, 10, 0.0380),
+    (3, 11, 0.0045),
+    (3, 6, 0.0024);
+
+-- Ingredient Substitutes
+INSERT INTO ingredient_substitutes (ingredient_id, substitute_id, ratio, priority) VALUES
+    (2, 29, 1, 0),     -- Milk -> Oat Milk
+    (9, 2, 1.5, 0),    -- Cream -> Milk, more of it for the same body
+    (6, 14, 0.75, 0);  -- Butter -> Olive Oil in baking
+
+-- Menu Item Translations
+INSERT INTO menu_item_translations (menu_item_id, locale, name, description, allergens) VALUES
+    (1, 'de', 'Milchkaffee', 'Espresso mit aufgeschäumter Milch', ARRAY['Milch']::text[]),
+    (1, 'fr', 'Café au lait', 'Espresso au lait chaud', ARRAY['lait']::text[]),
+    (3, 'de', 'Blaubeermuffin', 'Muffin mit Blaubeeren', ARRAY['Gluten']::text[]),
+    (5, 'fr', 'Croissant', 'Viennoiserie pur beurre', ARRAY['gluten']::text[]);
+
 -- Menu Item Ingredients
 INSERT INTO menu_item_ingredients (menu_item_id, ingredient_id, quantity, unit) VALUES
     (1, 1, 30, 'g'),    -- Latte: Coffee Beans
@@ -180,7 +534,7 @@ INSERT INTO menu_item_ingredients (menu_item_id, ingredient_id, quantity, unit)
     (8, 5, 200, 'g'),   -- Chocolate Cake: Flour
     (8, 8, 100, 'g'),   -- Chocolate Cake: Chocolate
     (9, 1, 30, 'g'),    -- Oat Latte: Coffee Beans
-    (9, 14, 200, 'ml'), -- Oat Latte: Olive Oil (предполагаем, что это замена для oat milk, так как oat milk отсутствует)
+    (9, 29, 200, 'ml'), -- Oat Latte: Oat Milk
     (10, 5, 150, 'g'),  -- Cinnamon Roll: Flour
     (10, 10, 5, 'g');   -- Cinnamon Roll: Cinnamon
 
@@ -272,10 +626,51 @@ INSERT INTO price_history (menu_item_id, old_price, new_price, changed_at) VALUE
     (8, 6.00, 6.50, '2025-02-15 00:00:00+00');
 
 -- Inventory Transactions
-INSERT INTO inventory_transactions (ingredient_id, change_amount, transaction_type, occurred_at) VALUES
-    (1, 1000, 'purchase', '2025-03-01 08:00:00+00'),
-    (1, -30, 'use', '2025-03-20 10:00:00+00'),
-    (2, 5000, 'purchase', '2025-03-01 08:00:00+00'),
-    (2, -200, 'use', '2025-03-20 10:00:00+00'),
-    (5, 10000, 'purchase', '2025-03-01 08:00:00+00'),
-    (5, -150, 'use', '2025-03-25 09:00:00+00');
\ No newline at end of file
+INSERT INTO inventory_transactions (ingredient_id, change_amount, transaction_type, unit_cost, occurred_at) VALUES
+    (1, 1000, 'purchase', 0.0095, '2025-03-01 08:00:00+00'),
+    (1, -30, 'use', NULL, '2025-03-20 10:00:00+00'),
+    (2, 5000, 'purchase', 0.0021, '2025-03-01 08:00:00+00'),
+    (2, -200, 'use', NULL, '2025-03-20 10:00:00+00'),
+    (5, 10000, 'purchase', 0.0007, '2025-03-01 08:00:00+00'),
+    (5, -150, 'use', NULL, '2025-03-25 09:00:00+00');
+
+INSERT INTO inventory_transactions (ingredient_id, change_amount, transaction_type, reason, note, occurred_at) VALUES
+    (2, -500, 'waste', 'expired', 'carton past its date', '2025-03-22 18:00:00+00'),
+    (2, -150, 'waste', 'spilled', 'steaming pitcher knocked over', '2025-03-24 11:30:00+00'),
+    (9, -200, 'waste', 'expired', '', '2025-03-26 18:00:00+00'),
+    (7, -6, 'waste', 'quality', 'cracked on delivery', '2025-03-02 09:00:00+00');
+
+-- Opening balances, so that the ledger of every item sums up to inventory.stock
+INSERT INTO inventory_transactions (ingredient_id, change_amount, transaction_type, note, occurred_at)
+SELECT i.id, i.stock - COALESCE(SUM(t.change_amount), 0), 'adjustment', 'opening balance', '2025-02-28 08:00:00+00'
+FROM inventory i
+LEFT JOIN inventory_transactions t ON t.ingredient_id = i.id
+GROUP BY i.id, i.stock
+HAVING i.stock - COALESCE(SUM(t.change_amount), 0) <> 0;
+
+-- Opening lots; dairy is perishable and gets a use-by date a week ahead
+INSERT INTO inventory_lots (ingredient_id, quantity, remaining, expires_at, received_at, note)
+SELECT id, stock, stock,
+       CASE WHEN name IN ('Milk', 'Cream', 'Oat Milk') THEN CURRENT_DATE + 7 END,
+       '2025-02-28 08:00:00+00', 'opening balance'
+FROM inventory
+WHERE stock > 0;
+
+-- Migrate free-text menu_items.categories into the categories resource.
+-- Names are normalised (trim + lower case) so "Coffee" and "coffee " become one category.
+INSERT INTO categories (name, display_order)
+SELECT name, ROW_NUMBER() OVER (ORDER BY name)
+FROM (
+    SELECT DISTINCT LOWER(TRIM(c.name)) AS name
+    FROM menu_items mi
+    CROSS JOIN LATERAL UNNEST(mi.categories) AS c(name)
+    WHERE TRIM(c.name) <> ''
+) names;
+
+INSERT INTO menu_item_categories (menu_item_id, category_id)
+SELECT DISTINCT mi.id, cat.id
+FROM menu_items mi
+CROSS JOIN LATERAL UNNEST(mi.categories) AS c(name)
+JOIN categories cat ON cat.name = LOWER(TRIM(c.name));
+
+ALTER TABLE menu_items DROP COLUMN categories;
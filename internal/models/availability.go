@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// AvailabilityWindow описывает, в какие дни недели и часы позиция меню доступна для заказа.
+// DaysOfWeek использует нумерацию time.Weekday (0 = воскресенье); пустой список — каждый день.
+type AvailabilityWindow struct {
+	ID         int    `json:"id"`
+	MenuItemID int    `json:"menu_item_id"`
+	DaysOfWeek []int  `json:"days_of_week"`
+	StartTime  string `json:"start_time"`
+	EndTime    string `json:"end_time"`
+}
+
+// PriceRule — скидка по времени (например, happy hour) для позиции или категории.
+type PriceRule struct {
+	ID              int     `json:"id"`
+	Name            string  `json:"name"`
+	MenuItemID      *int    `json:"menu_item_id"`
+	Category        string  `json:"category"`
+	DiscountPercent float64 `json:"discount_percent"`
+	DaysOfWeek      []int   `json:"days_of_week"`
+	StartTime       string  `json:"start_time"`
+	EndTime         string  `json:"end_time"`
+	Active          bool    `json:"active"`
+}
+
+type MenuFilter struct {
+	All      bool
+	At       time.Time
+	Category string
+	// LocationID — кафе, по складу которого считаются portions_available и sold_out;
+	// 0 — по общему остатку всех локаций
+	LocationID int
+	// Languages — предпочитаемые языки клиента по убыванию приоритета
+	Languages []string
+	// Ограничения по пищевой ценности на порцию; nil — без ограничения
+	MaxCalories *float64
+	MaxSugar    *float64
+	MaxCaffeine *float64
+}
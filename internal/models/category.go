@@ -0,0 +1,21 @@
+package models
+
+type Category struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	DisplayOrder int    `json:"display_order"`
+	ParentID     *int   `json:"parent_id"`
+}
+
+// CategoryNode — категория с подкатегориями и позициями для GET /menu/tree.
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+	Items    []MenuItem     `json:"items"`
+}
+
+type MenuTree struct {
+	Categories    []CategoryNode `json:"categories"`
+	Uncategorized []MenuItem     `json:"uncategorized"`
+}
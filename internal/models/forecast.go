@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// SalesSlot — сколько позиции продано за окно истории в данный день недели и час.
+type SalesSlot struct {
+	MenuItemID int
+	Weekday    time.Weekday
+	Hour       int
+	Quantity   float64
+}
+
+type DailyQuantity struct {
+	Date     string  `json:"date"`
+	Quantity float64 `json:"quantity"`
+}
+
+// IngredientForecast — прогноз расхода ингредиента в единицах склада. RunsOutOn —
+// день, в который прогнозный расход превысит текущий остаток.
+type IngredientForecast struct {
+	IngredientID int             `json:"ingredient_id"`
+	Name         string          `json:"name"`
+	Unit         string          `json:"unit"`
+	Stock        float64         `json:"stock"`
+	Total        float64         `json:"total"`
+	Daily        []DailyQuantity `json:"daily"`
+	RunsOutOn    *string         `json:"runs_out_on"`
+}
+
+type ForecastReport struct {
+	From        time.Time            `json:"from"`
+	Days        int                  `json:"days"`
+	Weeks       int                  `json:"history_weeks"`
+	Ingredients []IngredientForecast `json:"ingredients"`
+}
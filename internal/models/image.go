@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type MenuItemImage struct {
+	ID           int       `json:"id"`
+	MenuItemID   int       `json:"menu_item_id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Имена файлов в хранилище, наружу отдаются только URL
+	FileName      string `json:"-"`
+	ThumbnailName string `json:"-"`
+}
+
+// ImageUpload — загружаемый файл: имя из формы нужно только для сообщений об ошибках.
+type ImageUpload struct {
+	Name string
+	Data []byte
+}
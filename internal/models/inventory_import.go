@@ -0,0 +1,52 @@
+package models
+
+// Режимы импорта склада: set выставляет остаток равным колонке stock,
+// add прибавляет stock к остатку как поставку (накладная).
+const (
+	InventoryImportSet = "set"
+	InventoryImportAdd = "add"
+)
+
+// InventoryExportItem — позиция склада в формате выгрузки. Тот же формат
+// принимает импорт: позиция ищется по имени, а если не найдена — создаётся.
+// Пустые reorder_threshold и price при обновлении оставляют значения позиции.
+type InventoryExportItem struct {
+	ID               int      `json:"id,omitempty"`
+	Name             string   `json:"name"`
+	Stock            float64  `json:"stock"`
+	Unit             string   `json:"unit"`
+	ReorderThreshold *float64 `json:"reorder_threshold"`
+	Price            *float64 `json:"price"`
+}
+
+// InventoryImportChange — проверенная строка импорта: новая позиция (Item.ID == 0)
+// или обновление. С Delivered остаток увеличивается поставкой на это количество,
+// иначе выставляется равным Item.Stock корректировкой. UnitCost — закупочная
+// цена поставки, если она точнее цены позиции в центах; nil — цена позиции.
+type InventoryImportChange struct {
+	Item       InventoryItem
+	Delivered  *float64
+	UnitCost   *float64
+	LocationID int
+}
+
+// InventoryImportRow — результат обработки одной строки импорта. Row считается
+// с 1 по строкам данных, без заголовка CSV.
+type InventoryImportRow struct {
+	Row          int                    `json:"row"`
+	IngredientID int                    `json:"ingredient_id,omitempty"`
+	Name         string                 `json:"name"`
+	Action       string                 `json:"action"`
+	Changes      map[string]FieldChange `json:"changes,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+type InventoryImportResult struct {
+	Mode      string               `json:"mode"`
+	DryRun    bool                 `json:"dry_run"`
+	Applied   bool                 `json:"applied"`
+	Created   []InventoryImportRow `json:"created"`
+	Updated   []InventoryImportRow `json:"updated"`
+	Unchanged []InventoryImportRow `json:"unchanged"`
+	Rejected  []InventoryImportRow `json:"rejected"`
+}
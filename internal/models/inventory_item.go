@@ -1,18 +1,57 @@
 package models
 
 type InventoryItem struct {
-	ID               int     `json:"id"`
-	Name             string  `json:"name"`
-	Stock            float64 `json:"stock"`
+	ID    int     `json:"id"`
+	Name  string  `json:"name"`
+	Stock float64 `json:"stock"`
+	// AvailableStock — остаток без просроченных партий; только он идёт в заказы
+	AvailableStock   float64 `json:"available_stock"`
 	Unit             string  `json:"unit"`
 	ReorderThreshold float64 `json:"reorder_threshold"`
 	Price            float64 `json:"price"`
+	// Nutrition — на единицу unit; nil, если данных нет
+	Nutrition *Nutrition `json:"nutrition,omitempty"`
+}
+
+// LeftOverItem — позиция склада в отчёте об остатках со стоимостью остатка
+// по цене позиции.
+type LeftOverItem struct {
+	InventoryItem
+	StockValue float64 `json:"stock_value"`
+}
+
+// LeftOverFilter — параметры GET /inventory/getLeftOvers. Страница задаётся
+// либо Page, либо Cursor; After — разобранный Cursor.
+type LeftOverFilter struct {
+	SortBy         string
+	SortOrder      string
+	BelowThreshold bool
+	Unit           string
+	NamePrefix     string
+	MinStock       *float64
+	MaxStock       *float64
+	Page           int
+	PageSize       int
+	Cursor         string
+	After          *LeftOverCursor
+}
+
+// LeftOverCursor — последняя строка предыдущей страницы: значение ключа
+// сортировки (как текст из базы) и id. Сортировка запоминается, чтобы курсор
+// нельзя было применить к другому порядку.
+type LeftOverCursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Value     string `json:"v"`
+	ID        int    `json:"id"`
 }
 
 type InventoryResponse struct {
-	CurrentPage int             `json:"currentPage"`
-	HasNextPage bool            `json:"hasNextPage"`
-	PageSize    int             `json:"pageSize"`
-	TotalPages  int             `json:"totalPages"`
-	Data        []InventoryItem `json:"data"`
+	CurrentPage *int           `json:"currentPage,omitempty"` // nil при выдаче по курсору
+	HasNextPage bool           `json:"hasNextPage"`
+	PageSize    int            `json:"pageSize"`
+	TotalPages  *int           `json:"totalPages,omitempty"`
+	TotalItems  int            `json:"totalItems"`
+	NextCursor  string         `json:"nextCursor,omitempty"`
+	Data        []LeftOverItem `json:"data"`
 }
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+const (
+	TransactionPurchase   = "purchase"
+	TransactionUse        = "use"
+	TransactionWaste      = "waste"
+	TransactionAdjustment = "adjustment"
+	TransactionTransfer   = "transfer"
+	TransactionReturn     = "return"
+)
+
+const (
+	WasteExpired = "expired"
+	WasteSpilled = "spilled"
+	WasteQuality = "quality"
+)
+
+// InventoryTransaction — запись журнала движения остатков. ChangeAmount со знаком:
+// приход положительный, расход отрицательный. Balance — остаток после операции.
+type InventoryTransaction struct {
+	ID           int     `json:"id"`
+	IngredientID int     `json:"ingredient_id"`
+	ChangeAmount float64 `json:"change_amount"`
+	Type         string  `json:"transaction_type"`
+	Note         string  `json:"note,omitempty"`
+	Reason       string  `json:"reason,omitempty"` // причина списания, только у waste
+	OrderID      *int    `json:"order_id,omitempty"`
+	// PurchaseOrderID — заказ поставщику, по которому пришёл товар
+	PurchaseOrderID *int      `json:"purchase_order_id,omitempty"`
+	Balance         float64   `json:"balance"`
+	OccurredAt      time.Time `json:"occurred_at"`
+	// ExpiresAt — срок годности партии (YYYY-MM-DD) для поступлений; в журнале не хранится
+	ExpiresAt *string `json:"expires_at,omitempty"`
+	// UnitCost — закупочная цена единицы для поступлений; без неё приход
+	// оценивается по InventoryItem.Price
+	UnitCost *float64 `json:"unit_cost,omitempty"`
+	// LocationID — локация, где изменился остаток; 0 — основная
+	LocationID int `json:"location_id"`
+	// TransferID — перемещение между локациями, частью которого является запись
+	TransferID *int `json:"transfer_id,omitempty"`
+}
+
+type TransactionFilter struct {
+	StartDate string
+	EndDate   string
+	Type      string
+	// LocationID — 0 означает все локации
+	LocationID int
+}
+
+// LedgerBalance сравнивает остаток на складе с суммой журнала по ингредиенту.
+type LedgerBalance struct {
+	IngredientID int     `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Stock        float64 `json:"stock"`
+	LedgerSum    float64 `json:"ledger_sum"`
+	Difference   float64 `json:"difference"`
+}
+
+type LedgerCheck struct {
+	Consistent    bool            `json:"consistent"`
+	Checked       int             `json:"checked"`
+	Discrepancies []LedgerBalance `json:"discrepancies"`
+}
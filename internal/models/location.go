@@ -0,0 +1,21 @@
+package models
+
+// DefaultLocationID — основное кафе; используется, когда локация не указана.
+const DefaultLocationID = 1
+
+type Location struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// LocationStock — остаток ингредиента в локации по её партиям. InTransit —
+// количество, которое едет в локацию по неполученным перемещениям.
+type LocationStock struct {
+	IngredientID   int     `json:"ingredient_id"`
+	Name           string  `json:"name"`
+	Unit           string  `json:"unit"`
+	Stock          float64 `json:"stock"`
+	AvailableStock float64 `json:"available_stock"`
+	InTransit      float64 `json:"in_transit"`
+}
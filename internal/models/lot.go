@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// InventoryLot — партия ингредиента из одной поставки. Сумма Remaining по партиям
+// равна inventory.stock; расход списывается с партий по FIFO.
+type InventoryLot struct {
+	ID              int       `json:"id"`
+	IngredientID    int       `json:"ingredient_id"`
+	LocationID      int       `json:"location_id"`
+	Location        string    `json:"location,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	Unit            string    `json:"unit,omitempty"`
+	Quantity        float64   `json:"quantity"`
+	Remaining       float64   `json:"remaining"`
+	ExpiresAt       *string   `json:"expires_at"` // YYYY-MM-DD, nil — без срока годности
+	Expired         bool      `json:"expired"`
+	ReceivedAt      time.Time `json:"received_at"`
+	PurchaseOrderID *int      `json:"purchase_order_id,omitempty"`
+	Note            string    `json:"note,omitempty"`
+}
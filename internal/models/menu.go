@@ -1,21 +1,42 @@
 package models
 
+const (
+	MenuItemSingle = "single"
+	MenuItemBundle = "bundle"
+)
+
 type MenuItem struct {
 	ID          int                  `json:"id"`
+	Type        string               `json:"type"`
 	Name        string               `json:"name"`
 	Description string               `json:"description"`
 	Categories  []string             `json:"categories"`
+	CategoryIDs []int                `json:"category_ids"`
 	Allergens   []string             `json:"allergens"`
 	Price       float64              `json:"price"`
 	Available   bool                 `json:"available"`
 	Size        string               `json:"size"`
 	Ingredients []MenuItemIngredient `json:"ingredients,omitempty"`
+	Components  []BundleComponent    `json:"components,omitempty"`
+
+	AvailabilityWindows []AvailabilityWindow `json:"availability_windows,omitempty"`
+	AvailableNow        bool                 `json:"available_now"`
+	CurrentPrice        float64              `json:"current_price,omitempty"`
+	PortionsAvailable   *int                 `json:"portions_available"`
+	SoldOut             bool                 `json:"sold_out"`
+	Nutrition           *MenuItemNutrition   `json:"nutrition,omitempty"`
+
+	// Locale — язык, на котором отданы name, description и allergens
+	Locale       string                `json:"locale,omitempty"`
+	Translations []MenuItemTranslation `json:"translations,omitempty"`
+	Images       []MenuItemImage       `json:"images,omitempty"`
 }
 
 type PopularItem struct {
-	MenuItemID int    `json:"menu_item_id"`
-	Name       string `json:"name"`
-	Popularity int    `json:"popularity"`
+	MenuItemID int     `json:"menu_item_id"`
+	Name       string  `json:"name"`
+	Popularity int     `json:"popularity"`
+	Revenue    float64 `json:"revenue"`
 }
 
 type MenuItemIngredient struct {
@@ -23,3 +44,10 @@ type MenuItemIngredient struct {
 	Quantity     float64 `json:"quantity"`
 	Unit         string  `json:"unit"`
 }
+
+// BundleComponent — позиция меню, входящая в комбо-набор.
+type BundleComponent struct {
+	MenuItemID int    `json:"menu_item_id"`
+	Name       string `json:"name,omitempty"`
+	Quantity   int    `json:"quantity"`
+}
@@ -0,0 +1,47 @@
+package models
+
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionReject    = "reject"
+)
+
+// MenuExportItem — позиция меню в формате выгрузки. Тот же формат принимает импорт:
+// позиция с id обновляется, без id ищется по имени, а если не найдена — создаётся.
+// Отсутствующие (nil) description, size, price, categories, allergens и ingredients
+// и пустой type при обновлении не меняются.
+type MenuExportItem struct {
+	ID          int                  `json:"id,omitempty"`
+	Type        string               `json:"type,omitempty"`
+	Name        string               `json:"name"`
+	Description *string              `json:"description"`
+	Categories  []string             `json:"categories"`
+	Allergens   []string             `json:"allergens"`
+	Price       *float64             `json:"price"`
+	Available   *bool                `json:"available,omitempty"`
+	Size        *string              `json:"size"`
+	Ingredients []MenuItemIngredient `json:"ingredients"`
+	Components  []BundleComponent    `json:"components,omitempty"`
+}
+
+// MenuImportRow — результат обработки одной строки импорта. Row считается с 1
+// по строкам данных, без заголовка CSV.
+type MenuImportRow struct {
+	Row        int                    `json:"row"`
+	MenuItemID int                    `json:"menu_item_id,omitempty"`
+	Name       string                 `json:"name"`
+	Action     string                 `json:"action"`
+	Changes    map[string]FieldChange `json:"changes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+type MenuImportResult struct {
+	DryRun    bool            `json:"dry_run"`
+	Applied   bool            `json:"applied"`
+	Created   []MenuImportRow `json:"created"`
+	Updated   []MenuImportRow `json:"updated"`
+	Unchanged []MenuImportRow `json:"unchanged"`
+	Rejected  []MenuImportRow `json:"rejected"`
+	Version   *MenuVersion    `json:"version,omitempty"`
+}
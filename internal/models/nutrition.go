@@ -0,0 +1,17 @@
+package models
+
+// Nutrition — пищевая ценность. У ингредиента она задаётся на одну единицу
+// его unit (1 г, 1 мл, 1 шт), у позиции меню считается на порцию.
+type Nutrition struct {
+	Calories float64 `json:"calories"` // ккал
+	Fat      float64 `json:"fat"`      // г
+	Sugar    float64 `json:"sugar"`    // г
+	Protein  float64 `json:"protein"`  // г
+	Caffeine float64 `json:"caffeine"` // мг
+}
+
+type MenuItemNutrition struct {
+	Nutrition
+	// Complete — данные есть у всех ингредиентов рецептуры; иначе значения занижены
+	Complete bool `json:"complete"`
+}
@@ -10,6 +10,7 @@ type Order struct {
 	TotalAmount         float64     `json:"total_amount"`
 	PaymentMethod       string      `json:"payment_method"`
 	SpecialInstructions string      `json:"special_instructions"`
+	LocationID          int         `json:"location_id"` // кафе, со склада которого списываются ингредиенты
 	CreatedAt           time.Time   `json:"created_at"`
 	UpdatedAt           time.Time   `json:"updated_at"`
 }
@@ -21,6 +22,15 @@ type OrderItem struct {
 	Quantity       int     `json:"quantity"`
 	Price          float64 `json:"price"`
 	Customizations string  `json:"customizations"`
+	// Substitutions — ингредиенты, заменённые из-за нехватки на складе
+	Substitutions []OrderItemSubstitution `json:"substitutions,omitempty"`
+}
+
+// IngredientUsage — списание ингредиента под позицию заказа (ItemIndex — индекс в Order.Items).
+type IngredientUsage struct {
+	ItemIndex    int     `json:"-"`
+	IngredientID int     `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
 }
 
 type ProcessedOrder struct {
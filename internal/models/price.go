@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type PriceHistory struct {
+	ID         int       `json:"id"`
+	MenuItemID int       `json:"menu_item_id"`
+	OldPrice   float64   `json:"old_price"`
+	NewPrice   float64   `json:"new_price"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+type ScheduledPriceChange struct {
+	ID          int        `json:"id"`
+	MenuItemID  int        `json:"menu_item_id"`
+	NewPrice    float64    `json:"new_price"`
+	EffectiveAt time.Time  `json:"effective_at"`
+	AppliedAt   *time.Time `json:"applied_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+)
+
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name,omitempty"`
+	Status       string              `json:"status"`
+	Note         string              `json:"note"`
+	LocationID   int                 `json:"location_id"` // где принимается поставка
+	Lines        []PurchaseOrderLine `json:"lines"`
+	Total        float64             `json:"total"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	// ExpectedAt — дата отправки плюс срок поставки поставщика
+	ExpectedAt *time.Time `json:"expected_at,omitempty"`
+}
+
+// PurchaseOrderLine — строка заказа в единицах склада. UnitPrice по умолчанию
+// берётся из прайса поставщика.
+type PurchaseOrderLine struct {
+	ID               int     `json:"id"`
+	IngredientID     int     `json:"ingredient_id"`
+	Name             string  `json:"name,omitempty"`
+	Unit             string  `json:"unit,omitempty"`
+	Quantity         float64 `json:"quantity"`
+	ReceivedQuantity float64 `json:"received_quantity"`
+	UnitPrice        float64 `json:"unit_price"`
+}
+
+type PurchaseOrderFilter struct {
+	Status     string
+	SupplierID int
+}
+
+// ReceiptLine — сколько пришло по строке заказа; строку можно указать по
+// line_id или по ingredient_id. Каждая строка приёмки — отдельная партия.
+type ReceiptLine struct {
+	LineID       int     `json:"line_id"`
+	IngredientID int     `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
+	ExpiresAt    *string `json:"expires_at"` // YYYY-MM-DD
+}
+
+// PurchaseReceipt — тело POST /purchase-orders/{id}/receive. Без строк
+// принимается всё, что ещё не пришло.
+type PurchaseReceipt struct {
+	Lines []ReceiptLine `json:"lines"`
+	Note  string        `json:"note"`
+}
@@ -0,0 +1,38 @@
+package models
+
+// ReorderSuggestion — предложение дозаказа ингредиента. Quantity покрывает расход
+// на время поставки и горизонт планирования плюс reorder_threshold как страховой
+// запас, за вычетом остатка и того, что уже заказано.
+type ReorderSuggestion struct {
+	IngredientID     int      `json:"ingredient_id"`
+	Name             string   `json:"name"`
+	Unit             string   `json:"unit"`
+	Stock            float64  `json:"stock"`
+	OnOrder          float64  `json:"on_order"`
+	ReorderThreshold float64  `json:"reorder_threshold"`
+	DailyUsage       float64  `json:"daily_usage"`
+	DaysOfCover      *float64 `json:"days_of_cover"`
+	SupplierID       *int     `json:"supplier_id"`
+	SupplierName     string   `json:"supplier_name,omitempty"`
+	LeadTimeDays     int      `json:"lead_time_days"`
+	UnitPrice        float64  `json:"unit_price"`
+	Quantity         float64  `json:"quantity"`
+	EstimatedCost    float64  `json:"estimated_cost"`
+}
+
+type ReorderFilter struct {
+	// HorizonDays — на сколько дней после поставки должно хватить заказа
+	HorizonDays int `json:"horizon_days"`
+	// UsageDays — за сколько последних дней усредняется расход
+	UsageDays  int `json:"usage_days"`
+	SupplierID int `json:"supplier_id"`
+}
+
+// ReorderDraftRequest — тело POST /purchase-orders/from-suggestions.
+// IngredientIDs ограничивает набор предложений; пусто — берутся все.
+type ReorderDraftRequest struct {
+	ReorderFilter
+	IngredientIDs []int  `json:"ingredient_ids"`
+	Note          string `json:"note"`
+	LocationID    int    `json:"location_id"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// InventorySnapshot — остатки склада на момент AsOf, восстановленные по журналу.
+// LocationID = 0 — по всем локациям, без товара в пути.
+type InventorySnapshot struct {
+	AsOf       time.Time      `json:"as_of"`
+	LocationID int            `json:"location_id,omitempty"`
+	Items      []SnapshotItem `json:"items"`
+}
+
+type SnapshotItem struct {
+	IngredientID int     `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Stock        float64 `json:"stock"`
+}
+
+// SnapshotDiff — изменение остатков между двумя моментами с разбивкой движений
+// журнала по типам.
+type SnapshotDiff struct {
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	LocationID int                `json:"location_id,omitempty"`
+	Items      []SnapshotDiffItem `json:"items"`
+}
+
+type SnapshotDiffItem struct {
+	IngredientID int                `json:"ingredient_id"`
+	Name         string             `json:"name"`
+	Unit         string             `json:"unit"`
+	FromStock    float64            `json:"from_stock"`
+	ToStock      float64            `json:"to_stock"`
+	Change       float64            `json:"change"`
+	ByType       map[string]float64 `json:"by_type"`
+}
@@ -0,0 +1,19 @@
+package models
+
+const (
+	AlertLevelLow = "low"
+	AlertLevelOut = "out"
+)
+
+// StockAlert — ингредиент на пороге дозаказа или ниже. DaysOfCover — на сколько
+// дней хватит остатка при среднем расходе; nil, если расхода не было.
+type StockAlert struct {
+	IngredientID     int      `json:"ingredient_id"`
+	Name             string   `json:"name"`
+	Stock            float64  `json:"stock"`
+	Unit             string   `json:"unit"`
+	ReorderThreshold float64  `json:"reorder_threshold"`
+	DailyUsage       float64  `json:"daily_usage"`
+	DaysOfCover      *float64 `json:"days_of_cover"`
+	Level            string   `json:"level"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+const (
+	StockTakeOpen      = "open"
+	StockTakeCommitted = "committed"
+	StockTakeCancelled = "cancelled"
+)
+
+// StockTake — сессия инвентаризации: пересчитанные остатки сравниваются с учётными
+// и при фиксации проводятся в журнал корректировками.
+type StockTake struct {
+	ID          int          `json:"id"`
+	Status      string       `json:"status"`
+	LocationID  int          `json:"location_id"`
+	Note        string       `json:"note"`
+	StartedAt   time.Time    `json:"started_at"`
+	CommittedAt *time.Time   `json:"committed_at,omitempty"`
+	Counts      []StockCount `json:"counts,omitempty"`
+}
+
+// StockCount — пересчитанный остаток. SystemQuantity — учётный остаток в момент
+// подсчёта, чтобы продажи во время инвентаризации не попадали в расхождение.
+type StockCount struct {
+	IngredientID   int       `json:"ingredient_id"`
+	Name           string    `json:"name,omitempty"`
+	Unit           string    `json:"unit,omitempty"`
+	Counted        float64   `json:"counted"`
+	SystemQuantity float64   `json:"system_quantity"`
+	CountedAt      time.Time `json:"counted_at"`
+}
+
+// StockVariance — расхождение по ингредиенту. TheoreticalUsage — расход по
+// рецептурам с прошлой инвентаризации, ActualUsage — с учётом расхождения.
+type StockVariance struct {
+	IngredientID     int     `json:"ingredient_id"`
+	Name             string  `json:"name"`
+	Unit             string  `json:"unit"`
+	SystemQuantity   float64 `json:"system_quantity"`
+	Counted          float64 `json:"counted"`
+	Variance         float64 `json:"variance"`
+	ValueDifference  float64 `json:"value_difference"`
+	TheoreticalUsage float64 `json:"theoretical_usage"`
+	ActualUsage      float64 `json:"actual_usage"`
+}
+
+type VarianceReport struct {
+	StockTakeID          int             `json:"stock_take_id"`
+	Status               string          `json:"status"`
+	UsageSince           *time.Time      `json:"usage_since"`
+	Counted              int             `json:"counted"`
+	Uncounted            int             `json:"uncounted"`
+	TotalValueDifference float64         `json:"total_value_difference"`
+	Items                []StockVariance `json:"items"`
+}
+
+type StockCountRequest struct {
+	Counts []StockCount `json:"counts"`
+}
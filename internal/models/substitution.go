@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// IngredientSubstitute — разрешённая замена ингредиента: на единицу IngredientID
+// уходит Ratio единиц SubstituteID. Из нескольких замен берётся первая по Priority.
+type IngredientSubstitute struct {
+	IngredientID   int     `json:"ingredient_id"`
+	SubstituteID   int     `json:"substitute_id"`
+	SubstituteName string  `json:"substitute_name,omitempty"`
+	Ratio          float64 `json:"ratio"`
+	Priority       int     `json:"priority"`
+}
+
+// OrderItemSubstitution — замена, сделанная при приготовлении позиции заказа.
+// OriginalQuantity — сколько исходного ингредиента не хватило, Quantity — сколько списано замены.
+type OrderItemSubstitution struct {
+	IngredientID     int     `json:"ingredient_id"`
+	IngredientName   string  `json:"ingredient_name,omitempty"`
+	SubstituteID     int     `json:"substitute_id"`
+	SubstituteName   string  `json:"substitute_name,omitempty"`
+	OriginalQuantity float64 `json:"original_quantity"`
+	Quantity         float64 `json:"quantity"`
+}
+
+// SubstitutionRecord — строка отчёта о заменах для персонала.
+type SubstitutionRecord struct {
+	OrderID      int       `json:"order_id"`
+	MenuItemID   int       `json:"menu_item_id"`
+	MenuItemName string    `json:"menu_item_name"`
+	CreatedAt    time.Time `json:"created_at"`
+	OrderItemSubstitution
+}
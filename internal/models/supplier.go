@@ -0,0 +1,19 @@
+package models
+
+type Supplier struct {
+	ID           int            `json:"id"`
+	Name         string         `json:"name"`
+	ContactName  string         `json:"contact_name"`
+	Email        string         `json:"email"`
+	Phone        string         `json:"phone"`
+	LeadTimeDays int            `json:"lead_time_days"`
+	Items        []SupplierItem `json:"items"`
+}
+
+// SupplierItem — ингредиент, который поставляет поставщик, и цена за единицу склада.
+type SupplierItem struct {
+	IngredientID int     `json:"ingredient_id"`
+	Name         string  `json:"name,omitempty"`
+	Unit         string  `json:"unit,omitempty"`
+	UnitPrice    float64 `json:"unit_price"`
+}
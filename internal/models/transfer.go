@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+const (
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+)
+
+// StockTransfer — перемещение остатков между локациями. Источник списывается при
+// создании, получатель приходует при получении; пока товар в пути, его нет ни там, ни там.
+type StockTransfer struct {
+	ID             int            `json:"id"`
+	FromLocationID int            `json:"from_location_id"`
+	FromLocation   string         `json:"from_location,omitempty"`
+	ToLocationID   int            `json:"to_location_id"`
+	ToLocation     string         `json:"to_location,omitempty"`
+	Status         string         `json:"status"`
+	Note           string         `json:"note"`
+	CreatedAt      time.Time      `json:"created_at"`
+	ReceivedAt     *time.Time     `json:"received_at,omitempty"`
+	Lines          []TransferLine `json:"lines"`
+}
+
+// TransferLine — перемещаемое количество. Сохранённые строки разбиты по партиям
+// источника, ExpiresAt — срок годности партии.
+type TransferLine struct {
+	ID           int     `json:"id,omitempty"`
+	IngredientID int     `json:"ingredient_id"`
+	Name         string  `json:"name,omitempty"`
+	Unit         string  `json:"unit,omitempty"`
+	Quantity     float64 `json:"quantity"`
+	ExpiresAt    *string `json:"expires_at,omitempty"`
+}
+
+type TransferFilter struct {
+	Status     string
+	LocationID int
+}
@@ -0,0 +1,12 @@
+package models
+
+// DefaultLocale — язык, на котором хранятся поля самой позиции меню.
+const DefaultLocale = "en"
+
+type MenuItemTranslation struct {
+	MenuItemID  int      `json:"menu_item_id"`
+	Locale      string   `json:"locale"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Allergens   []string `json:"allergens"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	ValuationFIFO = "fifo"
+	ValuationWAC  = "wac"
+)
+
+type ValuationFilter struct {
+	// Method — fifo или wac
+	Method    string
+	StartDate string
+	EndDate   string
+	// Period — разбивка себестоимости продаж: day, week или month
+	Period string
+}
+
+// ItemValuation — оценка остатка ингредиента. UnitCost — средняя цена
+// единицы остатка при выбранном методе.
+type ItemValuation struct {
+	IngredientID int     `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Stock        float64 `json:"stock"`
+	UnitCost     float64 `json:"unit_cost"`
+	Value        float64 `json:"value"`
+}
+
+// COGSPeriod — себестоимость расхода по заказам (записи 'use') за период.
+type COGSPeriod struct {
+	Period string             `json:"period"`
+	Cost   float64            `json:"cost"`
+	ByItem map[string]float64 `json:"by_item"`
+}
+
+type ValuationReport struct {
+	Method     string          `json:"method"`
+	AsOf       time.Time       `json:"as_of"`
+	StartDate  string          `json:"start_date,omitempty"`
+	EndDate    string          `json:"end_date,omitempty"`
+	Period     string          `json:"period"`
+	TotalValue float64         `json:"total_value"`
+	TotalCOGS  float64         `json:"total_cogs"`
+	Items      []ItemValuation `json:"items"`
+	COGS       []COGSPeriod    `json:"cogs"`
+}
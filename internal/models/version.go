@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	DraftActionCreate = "create"
+	DraftActionUpdate = "update"
+	DraftActionDelete = "delete"
+)
+
+// MenuDraftChange — изменение меню, которое ждёт публикации.
+type MenuDraftChange struct {
+	ID         int       `json:"id"`
+	Action     string    `json:"action"`
+	MenuItemID *int      `json:"menu_item_id"`
+	Item       *MenuItem `json:"item,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// MenuDraft — накопленные изменения и меню, каким оно станет после публикации.
+type MenuDraft struct {
+	Changes []MenuDraftChange `json:"changes"`
+	Preview []MenuItem        `json:"preview"`
+}
+
+type MenuVersion struct {
+	ID          int        `json:"id"`
+	Note        string     `json:"note"`
+	PublishedAt time.Time  `json:"published_at"`
+	ItemCount   int        `json:"item_count"`
+	Items       []MenuItem `json:"items,omitempty"`
+}
+
+type MenuVersionDiff struct {
+	From    int            `json:"from"`
+	To      int            `json:"to"`
+	Added   []MenuItem     `json:"added"`
+	Removed []MenuItem     `json:"removed"`
+	Changed []MenuItemDiff `json:"changed"`
+}
+
+type MenuItemDiff struct {
+	MenuItemID int                    `json:"menu_item_id"`
+	Name       string                 `json:"name"`
+	Changes    map[string]FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// WasteEntry — тело POST /inventory/{id}/waste; Quantity положительное.
+type WasteEntry struct {
+	Quantity float64 `json:"quantity"`
+	Reason   string  `json:"reason"`
+	Note     string  `json:"note"`
+	// LocationID — 0 означает основную локацию
+	LocationID int `json:"location_id"`
+}
+
+type WasteFilter struct {
+	StartDate string
+	EndDate   string
+	// Period — day, week или month; пусто — без разбивки по периодам
+	Period string
+}
+
+// WasteSummary — списания ингредиента за период. Cost считается по текущей
+// цене InventoryItem.Price.
+type WasteSummary struct {
+	Period       string             `json:"period,omitempty"`
+	IngredientID int                `json:"ingredient_id"`
+	Name         string             `json:"name"`
+	Unit         string             `json:"unit"`
+	Quantity     float64            `json:"quantity"`
+	Cost         float64            `json:"cost"`
+	ByReason     map[string]float64 `json:"by_reason"`
+}
+
+type WasteReport struct {
+	StartDate string         `json:"start_date,omitempty"`
+	EndDate   string         `json:"end_date,omitempty"`
+	Period    string         `json:"period,omitempty"`
+	TotalCost float64        `json:"total_cost"`
+	Items     []WasteSummary `json:"items"`
+}
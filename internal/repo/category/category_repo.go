@@ -0,0 +1,140 @@
+package category
+
+import (
+	"database/sql"
+	"fmt"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+
+	"github.com/lib/pq"
+)
+
+type CategoryRepository interface {
+	GetAll() ([]models.Category, error)
+	GetByID(id int) (models.Category, error)
+	Create(category models.Category) (*models.Category, error)
+	Update(id int, category models.Category) error
+	Delete(id int) error
+}
+
+type categoryRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) CategoryRepository {
+	return &categoryRepository{
+		db: db,
+	}
+}
+
+const selectCategory = `
+        SELECT id, name, description, display_order, parent_id
+        FROM categories`
+
+func scanCategory(row interface{ Scan(...any) error }) (models.Category, error) {
+	var c models.Category
+	var parentID sql.NullInt64
+	if err := row.Scan(&c.ID, &c.Name, &c.Description, &c.DisplayOrder, &parentID); err != nil {
+		return models.Category{}, err
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		c.ParentID = &id
+	}
+	return c, nil
+}
+
+// isUniqueViolation — имя категории уникально без учёта регистра (idx_categories_name).
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
+
+func (r *categoryRepository) GetAll() ([]models.Category, error) {
+	rows, err := r.db.Query(selectCategory + ` ORDER BY display_order, name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %v", err)
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %v", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) GetByID(id int) (models.Category, error) {
+	c, err := scanCategory(r.db.QueryRow(selectCategory+` WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Category{}, cerrors.ErrNotExist
+		}
+		return models.Category{}, fmt.Errorf("failed to query category: %v", err)
+	}
+	return c, nil
+}
+
+func (r *categoryRepository) Create(category models.Category) (*models.Category, error) {
+	err := r.db.QueryRow(`
+        INSERT INTO categories (name, description, display_order, parent_id)
+        VALUES ($1, $2, $3, $4) RETURNING id`,
+		category.Name, category.Description, category.DisplayOrder, category.ParentID).
+		Scan(&category.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, cerrors.ErrExist
+		}
+		return nil, fmt.Errorf("failed to create category: %v", err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) Update(id int, category models.Category) error {
+	result, err := r.db.Exec(`
+        UPDATE categories
+        SET name = $1, description = $2, display_order = $3, parent_id = $4
+        WHERE id = $5`,
+		category.Name, category.Description, category.DisplayOrder, category.ParentID, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return cerrors.ErrExist
+		}
+		return fmt.Errorf("failed to update category: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return cerrors.ErrNotExist
+	}
+	return nil
+}
+
+// Delete удаляет категорию; подкатегории поднимаются на верхний уровень (ON DELETE SET NULL),
+// связи с позициями меню удаляются каскадно.
+func (r *categoryRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return cerrors.ErrNotExist
+	}
+	return nil
+}
@@ -3,10 +3,15 @@ package internal
 import (
 	"database/sql"
 
+	"frappuccino/internal/repo/category"
 	"frappuccino/internal/repo/invent"
+	"frappuccino/internal/repo/location"
+	"frappuccino/internal/repo/lot"
 	"frappuccino/internal/repo/menu"
 	"frappuccino/internal/repo/order"
+	"frappuccino/internal/repo/pricerule"
 	"frappuccino/internal/repo/search"
+	"frappuccino/internal/repo/supplier"
 )
 
 type Container struct {
@@ -14,6 +19,11 @@ type Container struct {
 	InventoryRepo invent.Inventory
 	OrderRepo     order.OrderRepository
 	SearchRepo    search.SearchRepository
+	PriceRuleRepo pricerule.PriceRuleRepository
+	CategoryRepo  category.CategoryRepository
+	SupplierRepo  supplier.SupplierRepository
+	LotRepo       lot.LotRepository
+	LocationRepo  location.LocationRepository
 }
 
 func New(path *sql.DB) *Container {
@@ -22,5 +32,10 @@ func New(path *sql.DB) *Container {
 		InventoryRepo: invent.New(path),
 		OrderRepo:     order.New(path),
 		SearchRepo:    search.New(path),
+		PriceRuleRepo: pricerule.New(path),
+		CategoryRepo:  category.New(path),
+		SupplierRepo:  supplier.New(path),
+		LotRepo:       lot.New(path),
+		LocationRepo:  location.New(path),
 	}
 }
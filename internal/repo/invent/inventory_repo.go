@@ -3,8 +3,11 @@ package invent
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
 
 	_ "github.com/lib/pq"
 )
@@ -13,11 +16,36 @@ type Inventory interface {
 	CreateInventory(data models.InventoryItem) error
 	GetInventoryId(id int) (models.InventoryItem, error)
 	GetInventory() ([]models.InventoryItem, error)
-	PutInventory(id int, data models.InventoryItem) error
+	PutInventory(id int, data models.InventoryItem, locationID int) error
+	ImportInventory(changes []models.InventoryImportChange) error
 	DeleteInvent(id int) error
 	GetByNameAndUnit(name, unit string) (models.InventoryItem, error)
-	GetLeftOversWithPagination(sortBy string, page int, pageSize int) ([]models.InventoryItem, error)
-	CountTotalInventoryItems() (int, error)
+	GetLeftOversWithPagination(filter models.LeftOverFilter) ([]models.LeftOverItem, []string, error)
+	CountLeftOvers(filter models.LeftOverFilter) (int, error)
+	RecordTransaction(t models.InventoryTransaction) (*models.InventoryTransaction, error)
+	GetTransactions(ingredientID int, filter models.TransactionFilter) ([]models.InventoryTransaction, error)
+	GetLedgerBalances() ([]models.LedgerBalance, error)
+	GetDailyUsage(days int) (map[int]float64, error)
+	GetWasteSummary(filter models.WasteFilter) ([]models.WasteSummary, error)
+	CreateStockTake(data models.StockTake) (*models.StockTake, error)
+	GetStockTakes() ([]models.StockTake, error)
+	GetStockTake(id int) (models.StockTake, error)
+	SetStockCounts(id int, counts []models.StockCount) error
+	CommitStockTake(id int) ([]models.InventoryTransaction, error)
+	CancelStockTake(id int) error
+	GetRecipeUsage(locationID int, since *time.Time, until time.Time) (map[int]float64, error)
+	GetCostLedger() ([]models.InventoryTransaction, error)
+	GetStockAsOf(asOf time.Time, locationID int) ([]models.SnapshotItem, error)
+	GetMovements(from, to time.Time, locationID int) (map[int]map[string]float64, error)
+	GetTransfers(filter models.TransferFilter) ([]models.StockTransfer, error)
+	GetTransfer(id int) (models.StockTransfer, error)
+	CreateTransfer(data models.StockTransfer) (int, error)
+	ReceiveTransfer(id int) error
+	CancelTransfer(id int) error
+	GetSubstitutes() (map[int][]models.IngredientSubstitute, error)
+	GetSubstitutesByIngredientID(ingredientID int) ([]models.IngredientSubstitute, error)
+	SetSubstitute(sub models.IngredientSubstitute) error
+	DeleteSubstitute(ingredientID, substituteID int) error
 }
 
 type inventory struct {
@@ -30,13 +58,64 @@ func New(db *sql.DB) Inventory {
 	}
 }
 
-func (i *inventory) GetByNameAndUnit(name, unit string) (models.InventoryItem, error) {
+// Пищевая ценность лежит в inventory_nutrition; строки нет — данных нет.
+// Доступный остаток — без партий, у которых истёк срок годности.
+const inventoryColumns = `
+        i.id, i.name, i.stock, i.unit, i.reorder_threshold, i.price,
+        i.stock - COALESCE((SELECT SUM(l.remaining) FROM inventory_lots l
+                            WHERE l.ingredient_id = i.id AND l.expires_at < CURRENT_DATE), 0),
+        n.calories, n.fat, n.sugar, n.protein, n.caffeine`
+
+const inventoryFrom = `
+        FROM inventory i
+        LEFT JOIN inventory_nutrition n ON n.ingredient_id = i.id`
+
+const selectInventory = `
+        SELECT` + inventoryColumns + inventoryFrom
+
+func scanInventory(row interface{ Scan(...any) error }) (models.InventoryItem, error) {
 	var item models.InventoryItem
-	err := i.db.QueryRow(`
-        SELECT id, name, stock, unit, reorder_threshold
-        FROM inventory
-        WHERE name = $1 AND unit = $2`, name, unit).
-		Scan(&item.ID, &item.Name, &item.Stock, &item.Unit, &item.ReorderThreshold)
+	var calories, fat, sugar, protein, caffeine sql.NullFloat64
+	err := row.Scan(&item.ID, &item.Name, &item.Stock, &item.Unit, &item.ReorderThreshold, &item.Price,
+		&item.AvailableStock, &calories, &fat, &sugar, &protein, &caffeine)
+	if err != nil {
+		return models.InventoryItem{}, err
+	}
+	if calories.Valid {
+		item.Nutrition = &models.Nutrition{
+			Calories: calories.Float64,
+			Fat:      fat.Float64,
+			Sugar:    sugar.Float64,
+			Protein:  protein.Float64,
+			Caffeine: caffeine.Float64,
+		}
+	}
+	return item, nil
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func setNutrition(q execer, id int, n *models.Nutrition) error {
+	if n == nil {
+		return nil
+	}
+	_, err := q.Exec(`
+        INSERT INTO inventory_nutrition (ingredient_id, calories, fat, sugar, protein, caffeine)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (ingredient_id) DO UPDATE
+        SET calories = EXCLUDED.calories, fat = EXCLUDED.fat, sugar = EXCLUDED.sugar,
+            protein = EXCLUDED.protein, caffeine = EXCLUDED.caffeine`,
+		id, n.Calories, n.Fat, n.Sugar, n.Protein, n.Caffeine)
+	if err != nil {
+		return fmt.Errorf("failed to save nutrition: %v", err)
+	}
+	return nil
+}
+
+func (i *inventory) GetByNameAndUnit(name, unit string) (models.InventoryItem, error) {
+	item, err := scanInventory(i.db.QueryRow(selectInventory+` WHERE i.name = $1 AND i.unit = $2`, name, unit))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return models.InventoryItem{}, fmt.Errorf("item with name %s and unit %s not found", name, unit)
@@ -47,29 +126,59 @@ func (i *inventory) GetByNameAndUnit(name, unit string) (models.InventoryItem, e
 }
 
 func (i *inventory) CreateInventory(data models.InventoryItem) error {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	id, err := insertInventory(tx, data, models.DefaultLocationID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	data.ID = id
+	return nil
+}
+
+// insertInventory заводит позицию; начальный остаток — первая поставка в журнале
+// в локации locationID по цене позиции.
+func insertInventory(tx *sql.Tx, data models.InventoryItem, locationID int) (int, error) {
 	var id int
-	err := i.db.QueryRow(`
+	err := tx.QueryRow(`
         INSERT INTO inventory (name, stock, unit, reorder_threshold, price)
         VALUES ($1, $2, $3, $4, $5) RETURNING id`,
 		data.Name, data.Stock, data.Unit, data.ReorderThreshold, data.Price).
 		Scan(&id)
 	if err != nil {
-		return fmt.Errorf("failed to create inventory item: %v", err)
+		return 0, fmt.Errorf("failed to create inventory item: %v", err)
 	}
-	data.ID = id
-	return nil
+
+	if err := setNutrition(tx, id, data.Nutrition); err != nil {
+		return 0, err
+	}
+
+	if data.Stock != 0 {
+		t := models.InventoryTransaction{IngredientID: id, ChangeAmount: data.Stock, Type: models.TransactionPurchase,
+			Note: "initial stock", UnitCost: &data.Price, LocationID: locationID}
+		if err := logTransaction(tx, &t); err != nil {
+			return 0, err
+		}
+		if err := applyLots(tx, t); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
 }
 
 func (i *inventory) GetInventoryId(id int) (models.InventoryItem, error) {
-	var item models.InventoryItem
-	err := i.db.QueryRow(`
-        SELECT id, name, stock, unit, reorder_threshold
-        FROM inventory
-        WHERE id = $1`, id).
-		Scan(&item.ID, &item.Name, &item.Stock, &item.Unit, &item.ReorderThreshold)
+	item, err := scanInventory(i.db.QueryRow(selectInventory+` WHERE i.id = $1`, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return models.InventoryItem{}, fmt.Errorf("item with ID %d not found", id)
+			return models.InventoryItem{}, fmt.Errorf("item with ID %d not found: %w", id, cerrors.ErrNotExist)
 		}
 		return models.InventoryItem{}, fmt.Errorf("failed to query inventory item: %v", err)
 	}
@@ -77,9 +186,7 @@ func (i *inventory) GetInventoryId(id int) (models.InventoryItem, error) {
 }
 
 func (i *inventory) GetInventory() ([]models.InventoryItem, error) {
-	rows, err := i.db.Query(`
-        SELECT id, name, stock, unit, reorder_threshold
-        FROM inventory`)
+	rows, err := i.db.Query(selectInventory + ` ORDER BY i.id`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query inventory: %v", err)
 	}
@@ -87,8 +194,7 @@ func (i *inventory) GetInventory() ([]models.InventoryItem, error) {
 
 	var items []models.InventoryItem
 	for rows.Next() {
-		var item models.InventoryItem
-		err := rows.Scan(&item.ID, &item.Name, &item.Stock, &item.Unit, &item.ReorderThreshold)
+		item, err := scanInventory(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan inventory item: %v", err)
 		}
@@ -97,22 +203,128 @@ func (i *inventory) GetInventory() ([]models.InventoryItem, error) {
 	return items, nil
 }
 
-func (i *inventory) PutInventory(id int, upDate models.InventoryItem) error {
-	result, err := i.db.Exec(`
+// PutInventory не трогает пищевую ценность, если Nutrition не передан.
+// Разница со старым остатком записывается в журнал как корректировка в
+// локации locationID (0 — основная).
+func (i *inventory) PutInventory(id int, upDate models.InventoryItem, locationID int) error {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateInventory(tx, id, upDate, locationID, "stock set via inventory update"); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// updateInventory обновляет позицию, а разницу остатка пишет в журнал
+// корректировкой с пометкой note в локации locationID: остаток позиции общий
+// по всем локациям, а партии, с которых списывается уменьшение, — у каждой свои.
+func updateInventory(tx *sql.Tx, id int, upDate models.InventoryItem, locationID int, note string) error {
+	var oldStock float64
+	var oldUnit string
+	err := tx.QueryRow(`SELECT stock, unit FROM inventory WHERE id = $1 FOR UPDATE`, id).Scan(&oldStock, &oldUnit)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("item with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return fmt.Errorf("failed to check inventory: %v", err)
+	}
+
+	// Журнал, партии и закупочные цены хранятся в единице позиции: после смены
+	// единицы остатки на дату и оценка склада смешали бы старую и новую
+	if upDate.Unit != oldUnit {
+		var hasHistory bool
+		err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM inventory_transactions WHERE ingredient_id = $1)`, id).Scan(&hasHistory)
+		if err != nil {
+			return fmt.Errorf("failed to check inventory history: %v", err)
+		}
+		if hasHistory {
+			return fmt.Errorf("unit of item %d cannot be changed from %q to %q because it has stock history; create a new item instead", id, oldUnit, upDate.Unit)
+		}
+	}
+
+	_, err = tx.Exec(`
         UPDATE inventory 
-        SET name = $1, stock = $2, unit = $3, reorder_threshold = $4
-        WHERE id = $5`,
-		upDate.Name, upDate.Stock, upDate.Unit, upDate.ReorderThreshold, id)
+        SET name = $1, stock = $2, unit = $3, reorder_threshold = $4, price = $5
+        WHERE id = $6`,
+		upDate.Name, upDate.Stock, upDate.Unit, upDate.ReorderThreshold, upDate.Price, id)
 	if err != nil {
 		return fmt.Errorf("failed to update inventory item: %v", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if change := upDate.Stock - oldStock; change != 0 {
+		t := models.InventoryTransaction{IngredientID: id, ChangeAmount: change, Type: models.TransactionAdjustment, Note: note, LocationID: locationID}
+		if err := logTransaction(tx, &t); err != nil {
+			return err
+		}
+		if err := applyLots(tx, t); err != nil {
+			return err
+		}
+	}
+
+	return setNutrition(tx, id, upDate.Nutrition)
+}
+
+// receiveInventory обновляет позицию и прибавляет quantity к остатку поставкой
+// в локации locationID по закупочной цене unitCost, а без неё — по цене позиции.
+func receiveInventory(tx *sql.Tx, data models.InventoryItem, quantity float64, unitCost *float64, locationID int) error {
+	result, err := tx.Exec(`
+        UPDATE inventory
+        SET name = $1, stock = stock + $2, unit = $3, reorder_threshold = $4, price = $5
+        WHERE id = $6`,
+		data.Name, quantity, data.Unit, data.ReorderThreshold, data.Price, data.ID)
 	if err != nil {
+		return fmt.Errorf("failed to update inventory item: %v", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
 		return fmt.Errorf("failed to check rows affected: %v", err)
+	} else if n == 0 {
+		return fmt.Errorf("item with ID %d not found: %w", data.ID, cerrors.ErrNotExist)
 	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("item with ID %d not found", id)
+
+	t := models.InventoryTransaction{IngredientID: data.ID, ChangeAmount: quantity, Type: models.TransactionPurchase,
+		Note: "delivery via inventory import", UnitCost: unitCost, LocationID: locationID}
+	if t.UnitCost == nil {
+		t.UnitCost = &data.Price
+	}
+	if err := logTransaction(tx, &t); err != nil {
+		return err
+	}
+	return applyLots(tx, t)
+}
+
+// ImportInventory применяет проверенные строки импорта одной транзакцией:
+// либо загружается весь файл, либо ничего.
+func (i *inventory) ImportInventory(changes []models.InventoryImportChange) error {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for n, c := range changes {
+		switch {
+		case c.Item.ID == 0:
+			_, err = insertInventory(tx, c.Item, c.LocationID)
+		case c.Delivered != nil:
+			err = receiveInventory(tx, c.Item, *c.Delivered, c.UnitCost, c.LocationID)
+		default:
+			err = updateInventory(tx, c.Item.ID, c.Item, c.LocationID, "stock set via inventory import")
+		}
+		if err != nil {
+			return fmt.Errorf("import change %d (%q): %w", n+1, c.Item.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
 	}
 	return nil
 }
@@ -164,59 +376,120 @@ func (i *inventory) DeleteInvent(id int) error {
 	return nil
 }
 
-func (r *inventory) GetLeftOversWithPagination(sortBy string, page int, pageSize int) ([]models.InventoryItem, error) {
-	// Проверяем параметры на валидность
-	if page < 1 {
-		return nil, fmt.Errorf("invalid page parameter")
+// leftOverSorts — допустимые сортировки остатков: выражение SQL и тип, к
+// которому приводится значение из курсора.
+var leftOverSorts = map[string]struct{ expr, cast string }{
+	"name":     {"i.name", "text"},
+	"price":    {"i.price", "numeric"},
+	"quantity": {"i.stock", "numeric"},
+	"value":    {"ROUND(i.stock * i.price, 2)", "numeric"},
+}
+
+// leftOverConditions строит условия фильтра остатков; аргументы нумеруются
+// после уже переданных args.
+func leftOverConditions(filter models.LeftOverFilter, args []any) ([]string, []any) {
+	var conditions []string
+	if filter.BelowThreshold {
+		conditions = append(conditions, "i.stock <= i.reorder_threshold")
+	}
+	if filter.Unit != "" {
+		args = append(args, filter.Unit)
+		conditions = append(conditions, fmt.Sprintf("i.unit = $%d", len(args)))
 	}
-	if pageSize < 1 {
-		return nil, fmt.Errorf("invalid pageSize parameter")
+	if filter.NamePrefix != "" {
+		prefix := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(filter.NamePrefix)
+		args = append(args, prefix+"%")
+		conditions = append(conditions, fmt.Sprintf("i.name ILIKE $%d", len(args)))
 	}
+	if filter.MinStock != nil {
+		args = append(args, *filter.MinStock)
+		conditions = append(conditions, fmt.Sprintf("i.stock >= $%d", len(args)))
+	}
+	if filter.MaxStock != nil {
+		args = append(args, *filter.MaxStock)
+		conditions = append(conditions, fmt.Sprintf("i.stock <= $%d", len(args)))
+	}
+	return conditions, args
+}
+
+// extraScanner дописывает к сканируемым колонкам позиции дополнительные.
+type extraScanner struct {
+	row   interface{ Scan(...any) error }
+	extra []any
+}
+
+func (e extraScanner) Scan(dest ...any) error {
+	return e.row.Scan(append(dest, e.extra...)...)
+}
 
-	var query string
+// GetLeftOversWithPagination возвращает остатки по фильтру в порядке
+// filter.SortBy/SortOrder, при равных значениях — по id. С filter.After
+// страница начинается после строки курсора, иначе — по номеру Page. Строк
+// берётся на одну больше PageSize, чтобы знать, есть ли следующая страница;
+// вторым значением возвращается ключ сортировки каждой строки для курсора.
+func (r *inventory) GetLeftOversWithPagination(filter models.LeftOverFilter) ([]models.LeftOverItem, []string, error) {
+	sort, ok := leftOverSorts[filter.SortBy]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid sortBy parameter: %s", filter.SortBy)
+	}
+	direction, compare := "ASC", ">"
+	if filter.SortOrder == "desc" {
+		direction, compare = "DESC", "<"
+	}
 
-	// Формируем запрос в зависимости от параметра сортировки
-	switch sortBy {
-	case "price":
-		query = "SELECT id, name, stock, unit, reorder_threshold, price FROM inventory ORDER BY price LIMIT $1 OFFSET $2"
-	case "quantity":
-		query = "SELECT id, name, stock, unit, reorder_threshold, price FROM inventory ORDER BY stock LIMIT $1 OFFSET $2"
-	default:
-		query = "SELECT id, name, stock, unit, reorder_threshold, price FROM inventory ORDER BY name LIMIT $1 OFFSET $2"
+	conditions, args := leftOverConditions(filter, nil)
+	if filter.After != nil {
+		args = append(args, filter.After.Value, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, i.id) %s ($%d::%s, $%d)",
+			sort.expr, compare, len(args)-1, sort.cast, len(args)))
 	}
 
-	// Рассчитываем OFFSET для пагинации
-	offset := (page - 1) * pageSize
+	query := `
+        SELECT` + inventoryColumns + `, ROUND(i.stock * i.price, 2), (` + sort.expr + `)::text` + inventoryFrom
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.PageSize+1)
+	query += fmt.Sprintf(" ORDER BY %s %s, i.id %s LIMIT $%d", sort.expr, direction, direction, len(args))
+	if filter.After == nil {
+		args = append(args, (filter.Page-1)*filter.PageSize)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
 
-	// Выполняем запрос с пагинацией
-	rows, err := r.db.Query(query, pageSize, offset)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query inventory: %w", err)
+		return nil, nil, fmt.Errorf("failed to query inventory: %w", err)
 	}
 	defer rows.Close()
 
-	var items []models.InventoryItem
-	// Сканы для каждой строки результата
+	var items []models.LeftOverItem
+	var keys []string
 	for rows.Next() {
-		var item models.InventoryItem
-		if err := rows.Scan(&item.ID, &item.Name, &item.Stock, &item.Unit, &item.ReorderThreshold, &item.Price); err != nil {
-			return nil, fmt.Errorf("failed to scan inventory item: %w", err)
+		var item models.LeftOverItem
+		var key string
+		item.InventoryItem, err = scanInventory(extraScanner{rows, []any{&item.StockValue, &key}})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan inventory item: %w", err)
 		}
 		items = append(items, item)
+		keys = append(keys, key)
 	}
-
-	// Проверка на ошибки во время итерации по строкам
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed during row iteration: %w", err)
+		return nil, nil, fmt.Errorf("failed during row iteration: %w", err)
 	}
-
-	return items, nil
+	return items, keys, nil
 }
 
-func (r *inventory) CountTotalInventoryItems() (int, error) {
+// CountLeftOvers считает позиции, подходящие под фильтр остатков.
+func (r *inventory) CountLeftOvers(filter models.LeftOverFilter) (int, error) {
+	conditions, args := leftOverConditions(filter, nil)
+	query := "SELECT COUNT(*) FROM inventory i"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
 	var count int
-	err := r.db.QueryRow("SELECT COUNT(*) FROM inventory").Scan(&count)
-	if err != nil {
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
 		return 0, fmt.Errorf("failed to count inventory items: %w", err)
 	}
 	return count, nil
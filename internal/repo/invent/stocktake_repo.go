@@ -0,0 +1,275 @@
+package invent
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+
+	"github.com/lib/pq"
+)
+
+const selectStockTake = `SELECT id, status, location_id, note, started_at, committed_at FROM stock_takes`
+
+func scanStockTake(row interface{ Scan(...any) error }) (models.StockTake, error) {
+	var st models.StockTake
+	var committedAt sql.NullTime
+	if err := row.Scan(&st.ID, &st.Status, &st.LocationID, &st.Note, &st.StartedAt, &committedAt); err != nil {
+		return models.StockTake{}, err
+	}
+	if committedAt.Valid {
+		st.CommittedAt = &committedAt.Time
+	}
+	return st, nil
+}
+
+// CreateStockTake открывает инвентаризацию локации; открытой в локации может
+// быть только одна.
+func (i *inventory) CreateStockTake(data models.StockTake) (*models.StockTake, error) {
+	st, err := scanStockTake(i.db.QueryRow(`
+        INSERT INTO stock_takes (location_id, note) VALUES ($1, $2)
+        RETURNING id, status, location_id, note, started_at, committed_at`, data.LocationID, data.Note))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("another stock-take is already open at location %d: %w", data.LocationID, cerrors.ErrExist)
+		}
+		return nil, fmt.Errorf("failed to create stock-take: %v", err)
+	}
+	return &st, nil
+}
+
+func (i *inventory) GetStockTakes() ([]models.StockTake, error) {
+	rows, err := i.db.Query(selectStockTake + ` ORDER BY started_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock-takes: %v", err)
+	}
+	defer rows.Close()
+
+	result := []models.StockTake{}
+	for rows.Next() {
+		st, err := scanStockTake(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock-take: %v", err)
+		}
+		result = append(result, st)
+	}
+	return result, rows.Err()
+}
+
+// GetStockTake возвращает инвентаризацию вместе с подсчётами.
+func (i *inventory) GetStockTake(id int) (models.StockTake, error) {
+	st, err := scanStockTake(i.db.QueryRow(selectStockTake+` WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.StockTake{}, fmt.Errorf("stock-take with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return models.StockTake{}, fmt.Errorf("failed to query stock-take: %v", err)
+	}
+
+	rows, err := i.db.Query(`
+        SELECT c.ingredient_id, i.name, i.unit, c.counted, c.system_quantity, c.counted_at
+        FROM stock_take_counts c
+        JOIN inventory i ON i.id = c.ingredient_id
+        WHERE c.stock_take_id = $1
+        ORDER BY i.name`, id)
+	if err != nil {
+		return models.StockTake{}, fmt.Errorf("failed to query stock counts: %v", err)
+	}
+	defer rows.Close()
+
+	st.Counts = []models.StockCount{}
+	for rows.Next() {
+		var c models.StockCount
+		if err := rows.Scan(&c.IngredientID, &c.Name, &c.Unit, &c.Counted, &c.SystemQuantity, &c.CountedAt); err != nil {
+			return models.StockTake{}, fmt.Errorf("failed to scan stock count: %v", err)
+		}
+		st.Counts = append(st.Counts, c)
+	}
+	return st, rows.Err()
+}
+
+// lockOpenStockTake блокирует инвентаризацию до конца транзакции, проверяет,
+// что она ещё открыта, и возвращает её локацию.
+func lockOpenStockTake(tx *sql.Tx, id int) (int, error) {
+	var status string
+	var locationID int
+	err := tx.QueryRow(`SELECT status, location_id FROM stock_takes WHERE id = $1 FOR UPDATE`, id).Scan(&status, &locationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("stock-take with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return 0, fmt.Errorf("failed to check stock-take: %v", err)
+	}
+	if status != models.StockTakeOpen {
+		return 0, fmt.Errorf("stock-take %d is %s", id, status)
+	}
+	return locationID, nil
+}
+
+// SetStockCounts записывает подсчёты; повторный подсчёт ингредиента заменяет
+// прежний. Учётный остаток — по партиям локации на момент подсчёта.
+func (i *inventory) SetStockCounts(id int, counts []models.StockCount) error {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	locationID, err := lockOpenStockTake(tx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range counts {
+		result, err := tx.Exec(`
+            INSERT INTO stock_take_counts (stock_take_id, ingredient_id, counted, system_quantity, counted_at)
+            SELECT $1, i.id, $3,
+                   COALESCE((SELECT SUM(l.remaining) FROM inventory_lots l WHERE l.ingredient_id = i.id AND l.location_id = $4), 0),
+                   NOW()
+            FROM inventory i WHERE i.id = $2
+            ON CONFLICT (stock_take_id, ingredient_id) DO UPDATE
+            SET counted = EXCLUDED.counted, system_quantity = EXCLUDED.system_quantity, counted_at = EXCLUDED.counted_at`,
+			id, c.IngredientID, c.Counted, locationID)
+		if err != nil {
+			return fmt.Errorf("failed to save stock count: %v", err)
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return fmt.Errorf("item with ID %d not found: %w", c.IngredientID, cerrors.ErrNotExist)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// CommitStockTake проводит расхождения (подсчитано минус учётный остаток на момент
+// подсчёта) корректировками в журнал локации и закрывает инвентаризацию. Расхождение
+// применяется к текущему остатку, так что движения после подсчёта сохраняются.
+func (i *inventory) CommitStockTake(id int) ([]models.InventoryTransaction, error) {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	locationID, err := lockOpenStockTake(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(`
+        SELECT ingredient_id, counted - system_quantity
+        FROM stock_take_counts
+        WHERE stock_take_id = $1 AND counted <> system_quantity
+        ORDER BY ingredient_id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock counts: %v", err)
+	}
+	variances := make(map[int]float64)
+	var ids []int
+	for rows.Next() {
+		var ingredientID int
+		var variance float64
+		if err := rows.Scan(&ingredientID, &variance); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stock count: %v", err)
+		}
+		variances[ingredientID] = variance
+		ids = append(ids, ingredientID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query stock counts: %v", err)
+	}
+
+	note := fmt.Sprintf("stock-take %d", id)
+	var transactions []models.InventoryTransaction
+	for _, ingredientID := range ids {
+		t := models.InventoryTransaction{
+			IngredientID: ingredientID,
+			ChangeAmount: variances[ingredientID],
+			Type:         models.TransactionAdjustment,
+			Note:         note,
+			LocationID:   locationID,
+		}
+
+		var stock float64
+		if err := tx.QueryRow(`SELECT stock FROM inventory WHERE id = $1 FOR UPDATE`, ingredientID).Scan(&stock); err != nil {
+			return nil, fmt.Errorf("failed to check inventory: %v", err)
+		}
+		t.Balance = stock + t.ChangeAmount
+		if t.Balance < 0 {
+			return nil, fmt.Errorf("ingredient %d: variance %v exceeds the current stock %v, recount it", ingredientID, t.ChangeAmount, stock)
+		}
+
+		if _, err := tx.Exec(`UPDATE inventory SET stock = $1 WHERE id = $2`, t.Balance, ingredientID); err != nil {
+			return nil, fmt.Errorf("failed to update inventory: %v", err)
+		}
+		if err := logTransaction(tx, &t); err != nil {
+			return nil, err
+		}
+		if err := applyLots(tx, t); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+
+	if _, err := tx.Exec(`UPDATE stock_takes SET status = 'committed', committed_at = NOW() WHERE id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to commit stock-take: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return transactions, nil
+}
+
+func (i *inventory) CancelStockTake(id int) error {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenStockTake(tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE stock_takes SET status = 'cancelled' WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to cancel stock-take: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// GetRecipeUsage возвращает расход по заказам (записи 'use') в локации за
+// [since, until); since = nil — с начала журнала.
+func (i *inventory) GetRecipeUsage(locationID int, since *time.Time, until time.Time) (map[int]float64, error) {
+	rows, err := i.db.Query(`
+        SELECT ingredient_id, -SUM(change_amount)
+        FROM inventory_transactions
+        WHERE transaction_type = 'use' AND location_id = $1
+          AND ($2::timestamptz IS NULL OR occurred_at >= $2) AND occurred_at < $3
+        GROUP BY ingredient_id`, locationID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recipe usage: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int]float64)
+	for rows.Next() {
+		var id int
+		var usage float64
+		if err := rows.Scan(&id, &usage); err != nil {
+			return nil, fmt.Errorf("failed to scan recipe usage: %v", err)
+		}
+		result[id] = usage
+	}
+	return result, rows.Err()
+}
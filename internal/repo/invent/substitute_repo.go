@@ -0,0 +1,86 @@
+package invent
+
+import (
+	"fmt"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+const selectSubstitutes = `
+        SELECT s.ingredient_id, s.substitute_id, i.name, s.ratio, s.priority
+        FROM ingredient_substitutes s
+        JOIN inventory i ON i.id = s.substitute_id`
+
+func scanSubstitute(row interface{ Scan(...any) error }) (models.IngredientSubstitute, error) {
+	var sub models.IngredientSubstitute
+	err := row.Scan(&sub.IngredientID, &sub.SubstituteID, &sub.SubstituteName, &sub.Ratio, &sub.Priority)
+	return sub, err
+}
+
+// GetSubstitutes возвращает замены по ингредиентам в порядке приоритета.
+func (i *inventory) GetSubstitutes() (map[int][]models.IngredientSubstitute, error) {
+	rows, err := i.db.Query(selectSubstitutes + ` ORDER BY s.ingredient_id, s.priority, s.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query substitutes: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int][]models.IngredientSubstitute)
+	for rows.Next() {
+		sub, err := scanSubstitute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan substitute: %v", err)
+		}
+		result[sub.IngredientID] = append(result[sub.IngredientID], sub)
+	}
+	return result, rows.Err()
+}
+
+func (i *inventory) GetSubstitutesByIngredientID(ingredientID int) ([]models.IngredientSubstitute, error) {
+	rows, err := i.db.Query(selectSubstitutes+` WHERE s.ingredient_id = $1 ORDER BY s.priority, s.id`, ingredientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query substitutes: %v", err)
+	}
+	defer rows.Close()
+
+	result := []models.IngredientSubstitute{}
+	for rows.Next() {
+		sub, err := scanSubstitute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan substitute: %v", err)
+		}
+		result = append(result, sub)
+	}
+	return result, rows.Err()
+}
+
+// SetSubstitute добавляет замену или обновляет ratio и priority существующей.
+func (i *inventory) SetSubstitute(sub models.IngredientSubstitute) error {
+	_, err := i.db.Exec(`
+        INSERT INTO ingredient_substitutes (ingredient_id, substitute_id, ratio, priority)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (ingredient_id, substitute_id) DO UPDATE
+        SET ratio = EXCLUDED.ratio, priority = EXCLUDED.priority`,
+		sub.IngredientID, sub.SubstituteID, sub.Ratio, sub.Priority)
+	if err != nil {
+		return fmt.Errorf("failed to save substitute: %v", err)
+	}
+	return nil
+}
+
+func (i *inventory) DeleteSubstitute(ingredientID, substituteID int) error {
+	result, err := i.db.Exec(`DELETE FROM ingredient_substitutes WHERE ingredient_id = $1 AND substitute_id = $2`,
+		ingredientID, substituteID)
+	if err != nil {
+		return fmt.Errorf("failed to delete substitute: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return cerrors.ErrNotExist
+	}
+	return nil
+}
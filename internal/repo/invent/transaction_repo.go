@@ -0,0 +1,367 @@
+package invent
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"frappuccino/internal/models"
+	"frappuccino/internal/repo/lot"
+	"frappuccino/pkg/cerrors"
+)
+
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// logTransaction пишет строку журнала; без LocationID — в основной локации.
+// Вызывается в той же транзакции, что и изменение inventory.stock, иначе сумма
+// журнала разойдётся с остатком.
+func logTransaction(q queryRower, t *models.InventoryTransaction) error {
+	if t.LocationID == 0 {
+		t.LocationID = models.DefaultLocationID
+	}
+	err := q.QueryRow(`
+        INSERT INTO inventory_transactions (ingredient_id, change_amount, transaction_type, note, reason, order_id, purchase_order_id, unit_cost, location_id, transfer_id)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10) RETURNING id, occurred_at`,
+		t.IngredientID, t.ChangeAmount, t.Type, t.Note, t.Reason, t.OrderID, t.PurchaseOrderID, t.UnitCost, t.LocationID, t.TransferID).
+		Scan(&t.ID, &t.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("failed to log inventory transaction: %v", err)
+	}
+	return nil
+}
+
+// RecordTransaction меняет остаток на ChangeAmount и записывает это в журнал.
+// Остаток не может уйти в минус.
+func (i *inventory) RecordTransaction(t models.InventoryTransaction) (*models.InventoryTransaction, error) {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var stock float64
+	err = tx.QueryRow(`SELECT stock FROM inventory WHERE id = $1 FOR UPDATE`, t.IngredientID).Scan(&stock)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("item with ID %d not found: %w", t.IngredientID, cerrors.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to check inventory: %v", err)
+	}
+
+	t.Balance = stock + t.ChangeAmount
+	if t.Balance < 0 {
+		return nil, fmt.Errorf("insufficient stock for ingredient %d: %v in stock, change %v", t.IngredientID, stock, t.ChangeAmount)
+	}
+
+	if _, err := tx.Exec(`UPDATE inventory SET stock = $1 WHERE id = $2`, t.Balance, t.IngredientID); err != nil {
+		return nil, fmt.Errorf("failed to update inventory: %v", err)
+	}
+	if err := logTransaction(tx, &t); err != nil {
+		return nil, err
+	}
+	if err := applyLots(tx, t); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return &t, nil
+}
+
+// applyLots отражает движение в партиях локации: поступление заводит новую
+// партию, расход списывается с партий по FIFO.
+func applyLots(tx *sql.Tx, t models.InventoryTransaction) error {
+	if t.ChangeAmount < 0 {
+		_, err := lot.Consume(tx, t.IngredientID, t.LocationID, -t.ChangeAmount, false)
+		return err
+	}
+	_, err := lot.Receive(tx, models.InventoryLot{
+		IngredientID:    t.IngredientID,
+		LocationID:      t.LocationID,
+		Quantity:        t.ChangeAmount,
+		ExpiresAt:       t.ExpiresAt,
+		PurchaseOrderID: t.PurchaseOrderID,
+		Note:            t.Note,
+	})
+	return err
+}
+
+// GetTransactions возвращает журнал ингредиента, новые записи сверху. Остаток
+// после каждой операции (по всем локациям) считается по всему журналу и только
+// потом фильтруется.
+func (i *inventory) GetTransactions(ingredientID int, filter models.TransactionFilter) ([]models.InventoryTransaction, error) {
+	queryStr := `
+        SELECT id, ingredient_id, change_amount, transaction_type, note, COALESCE(reason, ''), order_id, purchase_order_id, unit_cost,
+               location_id, transfer_id, balance, occurred_at
+        FROM (
+            SELECT t.*, SUM(t.change_amount) OVER (ORDER BY t.occurred_at, t.id) AS balance
+            FROM inventory_transactions t
+            WHERE t.ingredient_id = $1
+        ) l`
+
+	var conditions []string
+	args := []any{ingredientID}
+	if filter.StartDate != "" {
+		args = append(args, filter.StartDate)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if filter.EndDate != "" {
+		args = append(args, filter.EndDate)
+		conditions = append(conditions, fmt.Sprintf("occurred_at <= $%d", len(args)))
+	}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		conditions = append(conditions, fmt.Sprintf("transaction_type = $%d", len(args)))
+	}
+	if filter.LocationID != 0 {
+		args = append(args, filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("location_id = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		queryStr += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	queryStr += " ORDER BY occurred_at DESC, id DESC"
+
+	rows, err := i.db.Query(queryStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory transactions: %v", err)
+	}
+	defer rows.Close()
+
+	result := []models.InventoryTransaction{}
+	for rows.Next() {
+		var t models.InventoryTransaction
+		var orderID, purchaseOrderID, transferID sql.NullInt64
+		var unitCost sql.NullFloat64
+		if err := rows.Scan(&t.ID, &t.IngredientID, &t.ChangeAmount, &t.Type, &t.Note, &t.Reason, &orderID, &purchaseOrderID, &unitCost,
+			&t.LocationID, &transferID, &t.Balance, &t.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory transaction: %v", err)
+		}
+		if orderID.Valid {
+			id := int(orderID.Int64)
+			t.OrderID = &id
+		}
+		if purchaseOrderID.Valid {
+			id := int(purchaseOrderID.Int64)
+			t.PurchaseOrderID = &id
+		}
+		if unitCost.Valid {
+			t.UnitCost = &unitCost.Float64
+		}
+		if transferID.Valid {
+			id := int(transferID.Int64)
+			t.TransferID = &id
+		}
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
+
+// GetLedgerBalances сравнивает inventory.stock с суммой журнала по каждому ингредиенту.
+func (i *inventory) GetLedgerBalances() ([]models.LedgerBalance, error) {
+	rows, err := i.db.Query(`
+        SELECT i.id, i.name, i.stock, COALESCE(SUM(t.change_amount), 0)
+        FROM inventory i
+        LEFT JOIN inventory_transactions t ON t.ingredient_id = i.id
+        GROUP BY i.id, i.name, i.stock
+        ORDER BY i.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ledger balances: %v", err)
+	}
+	defer rows.Close()
+
+	var result []models.LedgerBalance
+	for rows.Next() {
+		var b models.LedgerBalance
+		if err := rows.Scan(&b.IngredientID, &b.Name, &b.Stock, &b.LedgerSum); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger balance: %v", err)
+		}
+		result = append(result, b)
+	}
+	return result, rows.Err()
+}
+
+// GetDailyUsage возвращает средний дневной расход (списания по заказам и порча)
+// по каждому ингредиенту за последние days дней.
+func (i *inventory) GetDailyUsage(days int) (map[int]float64, error) {
+	rows, err := i.db.Query(`
+        SELECT ingredient_id, -SUM(change_amount) / $1::numeric
+        FROM inventory_transactions
+        WHERE transaction_type IN ('use', 'waste')
+          AND occurred_at >= NOW() - make_interval(days => $1::int)
+        GROUP BY ingredient_id`, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily usage: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int]float64)
+	for rows.Next() {
+		var id int
+		var usage float64
+		if err := rows.Scan(&id, &usage); err != nil {
+			return nil, fmt.Errorf("failed to scan daily usage: %v", err)
+		}
+		result[id] = usage
+	}
+	return result, rows.Err()
+}
+
+// wastePeriods — допустимые разбивки отчёта о списаниях и их выражения SQL.
+var wastePeriods = map[string]string{
+	"":      `''`,
+	"day":   `to_char(t.occurred_at, 'YYYY-MM-DD')`,
+	"week":  `to_char(date_trunc('week', t.occurred_at), 'YYYY-MM-DD')`,
+	"month": `to_char(t.occurred_at, 'YYYY-MM')`,
+}
+
+// GetWasteSummary суммирует списания по периоду и ингредиенту с разбивкой по
+// причинам. Стоимость — по текущей цене ингредиента.
+func (i *inventory) GetWasteSummary(filter models.WasteFilter) ([]models.WasteSummary, error) {
+	periodExpr, ok := wastePeriods[filter.Period]
+	if !ok {
+		return nil, fmt.Errorf("unsupported period: %s", filter.Period)
+	}
+
+	queryStr := `
+        SELECT ` + periodExpr + `, t.ingredient_id, i.name, i.unit, i.price, COALESCE(t.reason, ''), -SUM(t.change_amount)
+        FROM inventory_transactions t
+        JOIN inventory i ON i.id = t.ingredient_id
+        WHERE t.transaction_type = 'waste'`
+	var args []any
+	if filter.StartDate != "" {
+		args = append(args, filter.StartDate)
+		queryStr += fmt.Sprintf(" AND t.occurred_at >= $%d", len(args))
+	}
+	if filter.EndDate != "" {
+		args = append(args, filter.EndDate)
+		queryStr += fmt.Sprintf(" AND t.occurred_at <= $%d", len(args))
+	}
+	queryStr += " GROUP BY 1, 2, 3, 4, 5, 6 ORDER BY 1, 3"
+
+	rows, err := i.db.Query(queryStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waste: %v", err)
+	}
+	defer rows.Close()
+
+	var result []models.WasteSummary
+	index := make(map[string]int)
+	for rows.Next() {
+		var w models.WasteSummary
+		var price, quantity float64
+		var reason string
+		if err := rows.Scan(&w.Period, &w.IngredientID, &w.Name, &w.Unit, &price, &reason, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan waste: %v", err)
+		}
+		if reason == "" {
+			reason = "unspecified"
+		}
+
+		key := fmt.Sprintf("%s/%d", w.Period, w.IngredientID)
+		n, ok := index[key]
+		if !ok {
+			w.ByReason = make(map[string]float64)
+			result = append(result, w)
+			n = len(result) - 1
+			index[key] = n
+		}
+		result[n].Quantity += quantity
+		result[n].Cost += quantity * price
+		result[n].ByReason[reason] += quantity
+	}
+	return result, rows.Err()
+}
+
+// GetCostLedger возвращает журнал всех ингредиентов в хронологическом порядке
+// с закупочными ценами — по нему считается оценка остатков и себестоимость.
+func (i *inventory) GetCostLedger() ([]models.InventoryTransaction, error) {
+	rows, err := i.db.Query(`
+        SELECT id, ingredient_id, change_amount, transaction_type, unit_cost, transfer_id, occurred_at
+        FROM inventory_transactions
+        WHERE ingredient_id IS NOT NULL
+        ORDER BY ingredient_id, occurred_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cost ledger: %v", err)
+	}
+	defer rows.Close()
+
+	var result []models.InventoryTransaction
+	for rows.Next() {
+		var t models.InventoryTransaction
+		var unitCost sql.NullFloat64
+		var transferID sql.NullInt64
+		if err := rows.Scan(&t.ID, &t.IngredientID, &t.ChangeAmount, &t.Type, &unitCost, &transferID, &t.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cost ledger: %v", err)
+		}
+		if unitCost.Valid {
+			t.UnitCost = &unitCost.Float64
+		}
+		if transferID.Valid {
+			id := int(transferID.Int64)
+			t.TransferID = &id
+		}
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
+
+// GetStockAsOf восстанавливает остатки на момент asOf по журналу: в снимок
+// попадают позиции, у которых к этому моменту была хоть одна запись.
+// locationID = 0 — по всем локациям.
+func (i *inventory) GetStockAsOf(asOf time.Time, locationID int) ([]models.SnapshotItem, error) {
+	rows, err := i.db.Query(`
+        SELECT i.id, i.name, i.unit, SUM(t.change_amount)
+        FROM inventory i
+        JOIN inventory_transactions t ON t.ingredient_id = i.id
+        WHERE t.occurred_at < $1 AND ($2 = 0 OR t.location_id = $2)
+        GROUP BY i.id, i.name, i.unit
+        ORDER BY i.id`, asOf, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock as of %s: %v", asOf.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	items := []models.SnapshotItem{}
+	for rows.Next() {
+		var item models.SnapshotItem
+		if err := rows.Scan(&item.IngredientID, &item.Name, &item.Unit, &item.Stock); err != nil {
+			return nil, fmt.Errorf("failed to scan stock: %v", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetMovements суммирует журнал в [from, to) по ингредиенту и типу движения.
+func (i *inventory) GetMovements(from, to time.Time, locationID int) (map[int]map[string]float64, error) {
+	rows, err := i.db.Query(`
+        SELECT ingredient_id, transaction_type, SUM(change_amount)
+        FROM inventory_transactions
+        WHERE ingredient_id IS NOT NULL AND occurred_at >= $1 AND occurred_at < $2
+          AND ($3 = 0 OR location_id = $3)
+        GROUP BY ingredient_id, transaction_type`, from, to, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query movements: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int]map[string]float64)
+	for rows.Next() {
+		var id int
+		var transactionType string
+		var amount float64
+		if err := rows.Scan(&id, &transactionType, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan movements: %v", err)
+		}
+		if result[id] == nil {
+			result[id] = make(map[string]float64)
+		}
+		result[id][transactionType] = amount
+	}
+	return result, rows.Err()
+}
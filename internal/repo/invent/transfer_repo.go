@@ -0,0 +1,311 @@
+package invent
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"frappuccino/internal/models"
+	"frappuccino/internal/repo/lot"
+	"frappuccino/pkg/cerrors"
+)
+
+const selectTransfer = `
+        SELECT t.id, t.from_location_id, f.name, t.to_location_id, d.name, t.status, t.note, t.created_at, t.received_at
+        FROM stock_transfers t
+        JOIN locations f ON f.id = t.from_location_id
+        JOIN locations d ON d.id = t.to_location_id`
+
+func scanTransfer(row interface{ Scan(...any) error }) (models.StockTransfer, error) {
+	var t models.StockTransfer
+	var receivedAt sql.NullTime
+	err := row.Scan(&t.ID, &t.FromLocationID, &t.FromLocation, &t.ToLocationID, &t.ToLocation, &t.Status, &t.Note, &t.CreatedAt, &receivedAt)
+	if err != nil {
+		return models.StockTransfer{}, err
+	}
+	if receivedAt.Valid {
+		t.ReceivedAt = &receivedAt.Time
+	}
+	return t, nil
+}
+
+// transferLines возвращает строки перемещений; transferID = 0 — всех.
+func (i *inventory) transferLines(transferID int) (map[int][]models.TransferLine, error) {
+	query := `
+        SELECT l.transfer_id, l.id, l.ingredient_id, i.name, i.unit, l.quantity, l.expires_at
+        FROM stock_transfer_lines l
+        JOIN inventory i ON i.id = l.ingredient_id`
+	var args []any
+	if transferID != 0 {
+		query += ` WHERE l.transfer_id = $1`
+		args = append(args, transferID)
+	}
+	rows, err := i.db.Query(query+` ORDER BY l.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfer lines: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int][]models.TransferLine)
+	for rows.Next() {
+		var id int
+		var l models.TransferLine
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&id, &l.ID, &l.IngredientID, &l.Name, &l.Unit, &l.Quantity, &expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer line: %v", err)
+		}
+		if expiresAt.Valid {
+			date := expiresAt.Time.Format(time.DateOnly)
+			l.ExpiresAt = &date
+		}
+		result[id] = append(result[id], l)
+	}
+	return result, rows.Err()
+}
+
+func (i *inventory) GetTransfers(filter models.TransferFilter) ([]models.StockTransfer, error) {
+	query := selectTransfer
+	var conditions []string
+	var args []any
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("t.status = $%d", len(args)))
+	}
+	if filter.LocationID != 0 {
+		args = append(args, filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("(t.from_location_id = $%d OR t.to_location_id = $%d)", len(args), len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := i.db.Query(query+` ORDER BY t.created_at DESC, t.id DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers: %v", err)
+	}
+	defer rows.Close()
+
+	transfers := []models.StockTransfer{}
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer: %v", err)
+		}
+		transfers = append(transfers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+
+	lines, err := i.transferLines(0)
+	if err != nil {
+		return nil, err
+	}
+	for n := range transfers {
+		transfers[n].Lines = lines[transfers[n].ID]
+	}
+	return transfers, nil
+}
+
+func (i *inventory) GetTransfer(id int) (models.StockTransfer, error) {
+	t, err := scanTransfer(i.db.QueryRow(selectTransfer+` WHERE t.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.StockTransfer{}, fmt.Errorf("transfer with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return models.StockTransfer{}, fmt.Errorf("failed to query transfer: %v", err)
+	}
+
+	lines, err := i.transferLines(id)
+	if err != nil {
+		return models.StockTransfer{}, err
+	}
+	t.Lines = lines[id]
+	return t, nil
+}
+
+// CreateTransfer списывает товар с партий источника (без просроченных) записями
+// 'transfer' и отправляет его в путь. Строки перемещения сохраняются по партиям,
+// чтобы у получателя остались сроки годности.
+func (i *inventory) CreateTransfer(data models.StockTransfer) (int, error) {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`
+        INSERT INTO stock_transfers (from_location_id, to_location_id, note)
+        VALUES ($1, $2, $3) RETURNING id`,
+		data.FromLocationID, data.ToLocationID, data.Note).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create transfer: %v", err)
+	}
+
+	// Ингредиенты блокируются в порядке id, как при списании по заказам
+	lines := append([]models.TransferLine(nil), data.Lines...)
+	sort.SliceStable(lines, func(a, b int) bool { return lines[a].IngredientID < lines[b].IngredientID })
+
+	note := fmt.Sprintf("transfer %d to location %d", id, data.ToLocationID)
+	for _, line := range lines {
+		var stock float64
+		err := tx.QueryRow(`SELECT stock FROM inventory WHERE id = $1 FOR UPDATE`, line.IngredientID).Scan(&stock)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return 0, fmt.Errorf("item with ID %d not found: %w", line.IngredientID, cerrors.ErrNotExist)
+			}
+			return 0, fmt.Errorf("failed to check inventory: %v", err)
+		}
+
+		t := models.InventoryTransaction{
+			IngredientID: line.IngredientID,
+			ChangeAmount: -line.Quantity,
+			Type:         models.TransactionTransfer,
+			Note:         note,
+			LocationID:   data.FromLocationID,
+			TransferID:   &id,
+			Balance:      stock - line.Quantity,
+		}
+		if t.Balance < 0 {
+			return 0, fmt.Errorf("insufficient stock for ingredient %d: %v in stock, transfer %v", line.IngredientID, stock, line.Quantity)
+		}
+		if _, err := tx.Exec(`UPDATE inventory SET stock = $1 WHERE id = $2`, t.Balance, line.IngredientID); err != nil {
+			return 0, fmt.Errorf("failed to update inventory: %v", err)
+		}
+		if err := logTransaction(tx, &t); err != nil {
+			return 0, err
+		}
+
+		taken, err := lot.Consume(tx, line.IngredientID, data.FromLocationID, line.Quantity, true)
+		if err != nil {
+			return 0, err
+		}
+		for _, part := range taken {
+			_, err := tx.Exec(`
+                INSERT INTO stock_transfer_lines (transfer_id, ingredient_id, quantity, expires_at)
+                VALUES ($1, $2, $3, $4)`,
+				id, line.IngredientID, part.Quantity, part.ExpiresAt)
+			if err != nil {
+				return 0, fmt.Errorf("failed to save transfer line: %v", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return id, nil
+}
+
+// lockTransfer блокирует перемещение в пути и возвращает его.
+func lockTransfer(tx *sql.Tx, id int) (models.StockTransfer, error) {
+	var t models.StockTransfer
+	err := tx.QueryRow(`
+        SELECT id, from_location_id, to_location_id, status
+        FROM stock_transfers WHERE id = $1 FOR UPDATE`, id).
+		Scan(&t.ID, &t.FromLocationID, &t.ToLocationID, &t.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return t, fmt.Errorf("transfer with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return t, fmt.Errorf("failed to check transfer: %v", err)
+	}
+	if t.Status != models.TransferInTransit {
+		return t, fmt.Errorf("transfer %d is already %s", id, t.Status)
+	}
+	return t, nil
+}
+
+// ReceiveTransfer приходует перемещение у получателя.
+func (i *inventory) ReceiveTransfer(id int) error {
+	return i.closeTransfer(id, models.TransferReceived)
+}
+
+// CancelTransfer возвращает товар в пути обратно источнику.
+func (i *inventory) CancelTransfer(id int) error {
+	return i.closeTransfer(id, models.TransferCancelled)
+}
+
+// closeTransfer приходует строки перемещения у получателя (received) или у
+// источника (cancelled) записями 'transfer' и партиями с прежними сроками годности.
+func (i *inventory) closeTransfer(id int, status string) error {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	transfer, err := lockTransfer(tx, id)
+	if err != nil {
+		return err
+	}
+	locationID := transfer.ToLocationID
+	note := fmt.Sprintf("transfer %d from location %d", id, transfer.FromLocationID)
+	if status == models.TransferCancelled {
+		locationID = transfer.FromLocationID
+		note = fmt.Sprintf("transfer %d cancelled", id)
+	}
+
+	rows, err := tx.Query(`
+        SELECT ingredient_id, quantity, expires_at FROM stock_transfer_lines
+        WHERE transfer_id = $1 ORDER BY ingredient_id, id`, id)
+	if err != nil {
+		return fmt.Errorf("failed to query transfer lines: %v", err)
+	}
+	var lines []models.TransferLine
+	for rows.Next() {
+		var l models.TransferLine
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&l.IngredientID, &l.Quantity, &expiresAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan transfer line: %v", err)
+		}
+		if expiresAt.Valid {
+			date := expiresAt.Time.Format(time.DateOnly)
+			l.ExpiresAt = &date
+		}
+		lines = append(lines, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query transfer lines: %v", err)
+	}
+
+	for _, line := range lines {
+		t := models.InventoryTransaction{
+			IngredientID: line.IngredientID,
+			ChangeAmount: line.Quantity,
+			Type:         models.TransactionTransfer,
+			Note:         note,
+			LocationID:   locationID,
+			TransferID:   &id,
+			ExpiresAt:    line.ExpiresAt,
+		}
+		err := tx.QueryRow(`UPDATE inventory SET stock = stock + $1 WHERE id = $2 RETURNING stock`, line.Quantity, line.IngredientID).Scan(&t.Balance)
+		if err != nil {
+			return fmt.Errorf("failed to update inventory: %v", err)
+		}
+		if err := logTransaction(tx, &t); err != nil {
+			return err
+		}
+		if err := applyLots(tx, t); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+        UPDATE stock_transfers
+        SET status = $1, received_at = CASE WHEN $1 = 'received' THEN NOW() END
+        WHERE id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
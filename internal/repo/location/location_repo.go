@@ -0,0 +1,108 @@
+package location
+
+import (
+	"database/sql"
+	"fmt"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+
+	"github.com/lib/pq"
+)
+
+type LocationRepository interface {
+	GetAll() ([]models.Location, error)
+	GetByID(id int) (models.Location, error)
+	Create(location models.Location) (*models.Location, error)
+	GetStock(id int) ([]models.LocationStock, error)
+}
+
+type locationRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) LocationRepository {
+	return &locationRepository{
+		db: db,
+	}
+}
+
+func (r *locationRepository) GetAll() ([]models.Location, error) {
+	rows, err := r.db.Query(`SELECT id, name, address FROM locations ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query locations: %v", err)
+	}
+	defer rows.Close()
+
+	locations := []models.Location{}
+	for rows.Next() {
+		var l models.Location
+		if err := rows.Scan(&l.ID, &l.Name, &l.Address); err != nil {
+			return nil, fmt.Errorf("failed to scan location: %v", err)
+		}
+		locations = append(locations, l)
+	}
+	return locations, rows.Err()
+}
+
+func (r *locationRepository) GetByID(id int) (models.Location, error) {
+	var l models.Location
+	err := r.db.QueryRow(`SELECT id, name, address FROM locations WHERE id = $1`, id).Scan(&l.ID, &l.Name, &l.Address)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Location{}, fmt.Errorf("location with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return models.Location{}, fmt.Errorf("failed to query location: %v", err)
+	}
+	return l, nil
+}
+
+func (r *locationRepository) Create(location models.Location) (*models.Location, error) {
+	err := r.db.QueryRow(`INSERT INTO locations (name, address) VALUES ($1, $2) RETURNING id`,
+		location.Name, location.Address).Scan(&location.ID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("location %q already exists: %w", location.Name, cerrors.ErrExist)
+		}
+		return nil, fmt.Errorf("failed to create location: %v", err)
+	}
+	return &location, nil
+}
+
+// GetStock возвращает остатки локации по её партиям и то, что едет в неё по
+// неполученным перемещениям. Ингредиенты без остатка и поставок в пути опускаются.
+func (r *locationRepository) GetStock(id int) ([]models.LocationStock, error) {
+	rows, err := r.db.Query(`
+        SELECT i.id, i.name, i.unit, COALESCE(l.stock, 0), COALESCE(l.available, 0), COALESCE(t.in_transit, 0)
+        FROM inventory i
+        LEFT JOIN (
+            SELECT ingredient_id, SUM(remaining) AS stock,
+                   SUM(remaining) FILTER (WHERE expires_at IS NULL OR expires_at >= CURRENT_DATE) AS available
+            FROM inventory_lots
+            WHERE location_id = $1
+            GROUP BY ingredient_id
+        ) l ON l.ingredient_id = i.id
+        LEFT JOIN (
+            SELECT tl.ingredient_id, SUM(tl.quantity) AS in_transit
+            FROM stock_transfer_lines tl
+            JOIN stock_transfers st ON st.id = tl.transfer_id
+            WHERE st.to_location_id = $1 AND st.status = 'in_transit'
+            GROUP BY tl.ingredient_id
+        ) t ON t.ingredient_id = i.id
+        WHERE COALESCE(l.stock, 0) > 0 OR COALESCE(t.in_transit, 0) > 0
+        ORDER BY i.name`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query location stock: %v", err)
+	}
+	defer rows.Close()
+
+	result := []models.LocationStock{}
+	for rows.Next() {
+		var s models.LocationStock
+		if err := rows.Scan(&s.IngredientID, &s.Name, &s.Unit, &s.Stock, &s.AvailableStock, &s.InTransit); err != nil {
+			return nil, fmt.Errorf("failed to scan location stock: %v", err)
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,174 @@
+package lot
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"frappuccino/internal/models"
+)
+
+type LotRepository interface {
+	GetLots(ingredientID, locationID int, all bool) ([]models.InventoryLot, error)
+	GetExpiring(days, locationID int) ([]models.InventoryLot, error)
+}
+
+type lotRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) LotRepository {
+	return &lotRepository{
+		db: db,
+	}
+}
+
+// Партия считается просроченной со дня после expires_at.
+const selectLot = `
+        SELECT l.id, l.ingredient_id, l.location_id, loc.name, i.name, i.unit, l.quantity, l.remaining, l.expires_at,
+               COALESCE(l.expires_at < CURRENT_DATE, FALSE), l.received_at, l.purchase_order_id, l.note
+        FROM inventory_lots l
+        JOIN inventory i ON i.id = l.ingredient_id
+        JOIN locations loc ON loc.id = l.location_id`
+
+// fifoOrder — порядок расхода партий: сначала ближайший срок годности, партии
+// без срока — последними, при равном сроке — более ранняя поставка.
+const fifoOrder = ` ORDER BY l.expires_at NULLS LAST, l.received_at, l.id`
+
+func scanLot(row interface{ Scan(...any) error }) (models.InventoryLot, error) {
+	var l models.InventoryLot
+	var expiresAt sql.NullTime
+	var purchaseOrderID sql.NullInt64
+	err := row.Scan(&l.ID, &l.IngredientID, &l.LocationID, &l.Location, &l.Name, &l.Unit, &l.Quantity, &l.Remaining, &expiresAt,
+		&l.Expired, &l.ReceivedAt, &purchaseOrderID, &l.Note)
+	if err != nil {
+		return models.InventoryLot{}, err
+	}
+	if expiresAt.Valid {
+		date := expiresAt.Time.Format(time.DateOnly)
+		l.ExpiresAt = &date
+	}
+	if purchaseOrderID.Valid {
+		id := int(purchaseOrderID.Int64)
+		l.PurchaseOrderID = &id
+	}
+	return l, nil
+}
+
+func (r *lotRepository) queryLots(query string, args ...any) ([]models.InventoryLot, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lots: %v", err)
+	}
+	defer rows.Close()
+
+	lots := []models.InventoryLot{}
+	for rows.Next() {
+		l, err := scanLot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lot: %v", err)
+		}
+		lots = append(lots, l)
+	}
+	return lots, rows.Err()
+}
+
+// GetLots возвращает партии ингредиента в порядке расхода; all — вместе с
+// израсходованными, locationID = 0 — во всех локациях.
+func (r *lotRepository) GetLots(ingredientID, locationID int, all bool) ([]models.InventoryLot, error) {
+	query := selectLot + ` WHERE l.ingredient_id = $1 AND ($2 = 0 OR l.location_id = $2)`
+	if !all {
+		query += ` AND l.remaining > 0`
+	}
+	return r.queryLots(query+fifoOrder, ingredientID, locationID)
+}
+
+// GetExpiring возвращает непустые партии, срок годности которых истекает в
+// ближайшие days дней, включая уже просроченные; locationID = 0 — во всех локациях.
+func (r *lotRepository) GetExpiring(days, locationID int) ([]models.InventoryLot, error) {
+	return r.queryLots(selectLot+`
+        WHERE l.remaining > 0 AND l.expires_at <= CURRENT_DATE + $1::int
+          AND ($2 = 0 OR l.location_id = $2)`+fifoOrder, days, locationID)
+}
+
+// Receive заводит партию на поступление в локации l.LocationID (0 — основная).
+// Вызывается в той же транзакции, что и увеличение inventory.stock.
+func Receive(tx *sql.Tx, l models.InventoryLot) (int, error) {
+	if l.LocationID == 0 {
+		l.LocationID = models.DefaultLocationID
+	}
+	var id int
+	err := tx.QueryRow(`
+        INSERT INTO inventory_lots (ingredient_id, location_id, quantity, remaining, expires_at, purchase_order_id, note)
+        VALUES ($1, $2, $3, $3, $4, $5, $6) RETURNING id`,
+		l.IngredientID, l.LocationID, l.Quantity, l.ExpiresAt, l.PurchaseOrderID, l.Note).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create lot: %v", err)
+	}
+	return id, nil
+}
+
+// Consume списывает quantity с партий ингредиента в локации locationID (0 — основная)
+// по FIFO и возвращает списанные части партий. С skipExpired просроченные партии
+// не трогаются — так списывается расход по заказам.
+// Вызывается в той же транзакции, что и уменьшение inventory.stock.
+func Consume(tx *sql.Tx, ingredientID, locationID int, quantity float64, skipExpired bool) ([]models.InventoryLot, error) {
+	if locationID == 0 {
+		locationID = models.DefaultLocationID
+	}
+	query := `
+        SELECT l.id, l.remaining, l.expires_at FROM inventory_lots l
+        WHERE l.ingredient_id = $1 AND l.location_id = $2 AND l.remaining > 0`
+	if skipExpired {
+		query += ` AND (l.expires_at IS NULL OR l.expires_at >= CURRENT_DATE)`
+	}
+	rows, err := tx.Query(query+fifoOrder+` FOR UPDATE`, ingredientID, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lots: %v", err)
+	}
+
+	var lots []models.InventoryLot
+	for rows.Next() {
+		l := models.InventoryLot{IngredientID: ingredientID, LocationID: locationID}
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&l.ID, &l.Remaining, &expiresAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan lot: %v", err)
+		}
+		if expiresAt.Valid {
+			date := expiresAt.Time.Format(time.DateOnly)
+			l.ExpiresAt = &date
+		}
+		lots = append(lots, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query lots: %v", err)
+	}
+
+	var taken []models.InventoryLot
+	left := quantity
+	for _, l := range lots {
+		if left <= 0 {
+			break
+		}
+		take := math.Min(l.Remaining, left)
+		if _, err := tx.Exec(`UPDATE inventory_lots SET remaining = remaining - $1 WHERE id = $2`, take, l.ID); err != nil {
+			return nil, fmt.Errorf("failed to update lot: %v", err)
+		}
+		l.Quantity = take
+		l.Remaining -= take
+		taken = append(taken, l)
+		left -= take
+	}
+
+	// Остатки хранятся с четырьмя знаками
+	if math.Round(left*10000) > 0 {
+		if skipExpired {
+			return nil, fmt.Errorf("insufficient unexpired stock for ingredient %d at location %d: %v short", ingredientID, locationID, left)
+		}
+		return nil, fmt.Errorf("insufficient stock in lots for ingredient %d at location %d: %v short", ingredientID, locationID, left)
+	}
+	return taken, nil
+}
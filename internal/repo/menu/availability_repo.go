@@ -0,0 +1,106 @@
+package menu
+
+import (
+	"fmt"
+
+	"frappuccino/internal/models"
+
+	"github.com/lib/pq"
+)
+
+func (r *menuRepository) GetAvailabilityWindows() (map[int][]models.AvailabilityWindow, error) {
+	rows, err := r.db.Query(`
+        SELECT id, menu_item_id, days_of_week, TO_CHAR(start_time, 'HH24:MI'), TO_CHAR(end_time, 'HH24:MI')
+        FROM menu_item_availability
+        ORDER BY menu_item_id, start_time`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query availability windows: %v", err)
+	}
+	defer rows.Close()
+
+	windows := make(map[int][]models.AvailabilityWindow)
+	for rows.Next() {
+		var w models.AvailabilityWindow
+		var days pq.Int64Array
+		if err := rows.Scan(&w.ID, &w.MenuItemID, &days, &w.StartTime, &w.EndTime); err != nil {
+			return nil, fmt.Errorf("failed to scan availability window: %v", err)
+		}
+		w.DaysOfWeek = toIntSlice(days)
+		windows[w.MenuItemID] = append(windows[w.MenuItemID], w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return windows, nil
+}
+
+func (r *menuRepository) GetAvailabilityWindowsByMenuItemID(menuItemID int) ([]models.AvailabilityWindow, error) {
+	rows, err := r.db.Query(`
+        SELECT id, menu_item_id, days_of_week, TO_CHAR(start_time, 'HH24:MI'), TO_CHAR(end_time, 'HH24:MI')
+        FROM menu_item_availability
+        WHERE menu_item_id = $1
+        ORDER BY start_time`, menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query availability windows: %v", err)
+	}
+	defer rows.Close()
+
+	windows := []models.AvailabilityWindow{}
+	for rows.Next() {
+		var w models.AvailabilityWindow
+		var days pq.Int64Array
+		if err := rows.Scan(&w.ID, &w.MenuItemID, &days, &w.StartTime, &w.EndTime); err != nil {
+			return nil, fmt.Errorf("failed to scan availability window: %v", err)
+		}
+		w.DaysOfWeek = toIntSlice(days)
+		windows = append(windows, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return windows, nil
+}
+
+// SetAvailabilityWindows заменяет все окна доступности позиции одним набором.
+func (r *menuRepository) SetAvailabilityWindows(menuItemID int, windows []models.AvailabilityWindow) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM menu_item_availability WHERE menu_item_id = $1`, menuItemID); err != nil {
+		return fmt.Errorf("failed to clear availability windows: %v", err)
+	}
+
+	for _, w := range windows {
+		_, err := tx.Exec(`
+            INSERT INTO menu_item_availability (menu_item_id, days_of_week, start_time, end_time)
+            VALUES ($1, $2, $3, $4)`,
+			menuItemID, pq.Array(toInt64Slice(w.DaysOfWeek)), w.StartTime, w.EndTime)
+		if err != nil {
+			return fmt.Errorf("failed to insert availability window: %v", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+func toIntSlice(values []int64) []int {
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		result = append(result, int(v))
+	}
+	return result
+}
+
+func toInt64Slice(values []int) []int64 {
+	result := make([]int64, 0, len(values))
+	for _, v := range values {
+		result = append(result, int64(v))
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package menu
+
+import (
+	"fmt"
+
+	"frappuccino/internal/models"
+)
+
+func (r *menuRepository) GetBundleComponents() (map[int][]models.BundleComponent, error) {
+	return queryBundleComponents(r.db)
+}
+
+func queryBundleComponents(q querier) (map[int][]models.BundleComponent, error) {
+	rows, err := q.Query(`
+        SELECT bc.bundle_id, bc.component_id, mi.name, bc.quantity
+        FROM bundle_components bc
+        JOIN menu_items mi ON mi.id = bc.component_id
+        ORDER BY bc.bundle_id, bc.component_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bundle components: %v", err)
+	}
+	defer rows.Close()
+
+	components := make(map[int][]models.BundleComponent)
+	for rows.Next() {
+		var bundleID int
+		var c models.BundleComponent
+		if err := rows.Scan(&bundleID, &c.MenuItemID, &c.Name, &c.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan bundle component: %v", err)
+		}
+		components[bundleID] = append(components[bundleID], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return components, nil
+}
+
+func (r *menuRepository) GetBundleComponentsByID(bundleID int) ([]models.BundleComponent, error) {
+	rows, err := r.db.Query(`
+        SELECT bc.component_id, mi.name, bc.quantity
+        FROM bundle_components bc
+        JOIN menu_items mi ON mi.id = bc.component_id
+        WHERE bc.bundle_id = $1
+        ORDER BY bc.component_id`, bundleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bundle components: %v", err)
+	}
+	defer rows.Close()
+
+	var components []models.BundleComponent
+	for rows.Next() {
+		var c models.BundleComponent
+		if err := rows.Scan(&c.MenuItemID, &c.Name, &c.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan bundle component: %v", err)
+		}
+		components = append(components, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return components, nil
+}
+
+// setBundleComponents заменяет состав комбо-набора.
+func setBundleComponents(q querier, bundleID int, components []models.BundleComponent) error {
+	if _, err := q.Exec(`DELETE FROM bundle_components WHERE bundle_id = $1`, bundleID); err != nil {
+		return fmt.Errorf("failed to clear bundle components: %v", err)
+	}
+
+	for _, c := range components {
+		_, err := q.Exec(`
+            INSERT INTO bundle_components (bundle_id, component_id, quantity)
+            VALUES ($1, $2, $3)`,
+			bundleID, c.MenuItemID, c.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to add bundle component: %v", err)
+		}
+	}
+	return nil
+}
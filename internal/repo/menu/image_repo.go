@@ -0,0 +1,88 @@
+package menu
+
+import (
+	"database/sql"
+	"fmt"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+const selectImage = `
+        SELECT id, menu_item_id, file_name, thumbnail_name, content_type, created_at
+        FROM menu_item_images`
+
+func scanImage(row interface{ Scan(...any) error }) (models.MenuItemImage, error) {
+	var img models.MenuItemImage
+	err := row.Scan(&img.ID, &img.MenuItemID, &img.FileName, &img.ThumbnailName, &img.ContentType, &img.CreatedAt)
+	return img, err
+}
+
+func (r *menuRepository) GetImages() (map[int][]models.MenuItemImage, error) {
+	rows, err := r.db.Query(selectImage + ` ORDER BY menu_item_id, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query images: %v", err)
+	}
+	defer rows.Close()
+
+	images := make(map[int][]models.MenuItemImage)
+	for rows.Next() {
+		img, err := scanImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan image: %v", err)
+		}
+		images[img.MenuItemID] = append(images[img.MenuItemID], img)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return images, nil
+}
+
+func (r *menuRepository) GetImagesByMenuItemID(menuItemID int) ([]models.MenuItemImage, error) {
+	rows, err := r.db.Query(selectImage+` WHERE menu_item_id = $1 ORDER BY id`, menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query images: %v", err)
+	}
+	defer rows.Close()
+
+	images := []models.MenuItemImage{}
+	for rows.Next() {
+		img, err := scanImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan image: %v", err)
+		}
+		images = append(images, img)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return images, nil
+}
+
+func (r *menuRepository) CreateImage(img models.MenuItemImage) (*models.MenuItemImage, error) {
+	err := r.db.QueryRow(`
+        INSERT INTO menu_item_images (menu_item_id, file_name, thumbnail_name, content_type)
+        VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		img.MenuItemID, img.FileName, img.ThumbnailName, img.ContentType).
+		Scan(&img.ID, &img.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save image: %v", err)
+	}
+	return &img, nil
+}
+
+// DeleteImage удаляет запись и возвращает её, чтобы сервис убрал файлы из хранилища.
+func (r *menuRepository) DeleteImage(menuItemID, imageID int) (*models.MenuItemImage, error) {
+	img, err := scanImage(r.db.QueryRow(`
+        DELETE FROM menu_item_images
+        WHERE id = $1 AND menu_item_id = $2
+        RETURNING id, menu_item_id, file_name, thumbnail_name, content_type, created_at`, imageID, menuItemID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, cerrors.ErrNotExist
+		}
+		return nil, fmt.Errorf("failed to delete image: %v", err)
+	}
+	return &img, nil
+}
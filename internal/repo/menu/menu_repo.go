@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"frappuccino/internal/models"
 	"frappuccino/pkg/cerrors"
@@ -13,55 +15,118 @@ import (
 )
 
 type MenuRepository interface {
-	CreateMenuItem(item models.MenuItem) (*models.MenuItem, error)
 	GetAllMenuItems() ([]models.MenuItem, error)
 	GetMenuItemByID(id int) (*models.MenuItem, error)
-	UpdateMenuItem(id int, item models.MenuItem) (*models.MenuItem, error)
-	DeleteMenuItem(id int) error
 	GetIngredientsByMenuItemID(menuItemID int) ([]models.MenuItemIngredient, error)
-	AddIngredientToMenuItem(menuItemID int, ingredient models.MenuItemIngredient) error
+	GetBundleComponents() (map[int][]models.BundleComponent, error)
+	GetBundleComponentsByID(bundleID int) ([]models.BundleComponent, error)
+	GetAllIngredients() (map[int][]models.MenuItemIngredient, error)
+	SetSoldOut(id int, soldOut bool) error
+	GetPriceHistory(menuItemID int) ([]models.PriceHistory, error)
+	CreateScheduledPriceChange(change models.ScheduledPriceChange) (*models.ScheduledPriceChange, error)
+	GetScheduledPriceChanges(menuItemID int) ([]models.ScheduledPriceChange, error)
+	DeleteScheduledPriceChange(menuItemID, changeID int) error
+	ApplyDuePriceChanges(now time.Time) ([]models.ScheduledPriceChange, error)
+	GetAvailabilityWindows() (map[int][]models.AvailabilityWindow, error)
+	GetAvailabilityWindowsByMenuItemID(menuItemID int) ([]models.AvailabilityWindow, error)
+	SetAvailabilityWindows(menuItemID int, windows []models.AvailabilityWindow) error
+	GetDraftChanges() ([]models.MenuDraftChange, error)
+	CreateDraftChange(change models.MenuDraftChange) (*models.MenuDraftChange, error)
+	DeleteDraftChange(id int) error
+	DiscardDraft() error
+	PublishDraft(note string) (*models.MenuVersion, error)
+	GetMenuVersions() ([]models.MenuVersion, error)
+	GetMenuVersion(id int) (*models.MenuVersion, error)
+	RollbackToVersion(id int, note string) (*models.MenuVersion, error)
+	ImportMenu(changes []models.MenuDraftChange, note string) (*models.MenuVersion, error)
+	ApplyMenuChange(change models.MenuDraftChange, note string) (int, *models.MenuVersion, error)
+	GetTranslations() (map[int][]models.MenuItemTranslation, error)
+	GetTranslationsByMenuItemID(menuItemID int) ([]models.MenuItemTranslation, error)
+	SetTranslation(t models.MenuItemTranslation) error
+	DeleteTranslation(menuItemID int, locale string) error
+	GetImages() (map[int][]models.MenuItemImage, error)
+	GetImagesByMenuItemID(menuItemID int) ([]models.MenuItemImage, error)
+	CreateImage(img models.MenuItemImage) (*models.MenuItemImage, error)
+	DeleteImage(menuItemID, imageID int) (*models.MenuItemImage, error)
 }
 
 type menuRepository struct {
 	db *sql.DB
 }
 
+// querier — общее у *sql.DB и *sql.Tx, чтобы одни и те же запросы работали и в транзакции.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// Категории хранятся в menu_item_categories; имена и id собираем подзапросами
+// в порядке отображения категорий.
+const menuItemColumns = `
+        mi.id, mi.item_type, mi.name, mi.description,
+        ARRAY(SELECT c.name FROM menu_item_categories mc JOIN categories c ON c.id = mc.category_id
+              WHERE mc.menu_item_id = mi.id ORDER BY c.display_order, c.name),
+        ARRAY(SELECT c.id FROM menu_item_categories mc JOIN categories c ON c.id = mc.category_id
+              WHERE mc.menu_item_id = mi.id ORDER BY c.display_order, c.name),
+        mi.allergens, mi.price, mi.available, mi.sold_out, mi.size`
+
+func scanMenuItem(row interface{ Scan(...any) error }) (models.MenuItem, error) {
+	var item models.MenuItem
+	var categoryIDs pq.Int64Array
+	err := row.Scan(&item.ID, &item.Type, &item.Name, &item.Description, pq.Array(&item.Categories), &categoryIDs,
+		pq.Array(&item.Allergens), &item.Price, &item.Available, &item.SoldOut, &item.Size)
+	if err != nil {
+		return models.MenuItem{}, err
+	}
+	item.CategoryIDs = toIntSlice(categoryIDs)
+	return item, nil
+}
+
+func setMenuItemCategories(q querier, menuItemID int, categoryIDs []int) error {
+	if _, err := q.Exec(`DELETE FROM menu_item_categories WHERE menu_item_id = $1`, menuItemID); err != nil {
+		return fmt.Errorf("failed to clear menu item categories: %v", err)
+	}
+	for _, categoryID := range categoryIDs {
+		_, err := q.Exec(`
+            INSERT INTO menu_item_categories (menu_item_id, category_id)
+            VALUES ($1, $2) ON CONFLICT DO NOTHING`, menuItemID, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to link menu item to category: %v", err)
+		}
+	}
+	return nil
+}
+
 func New(db *sql.DB) MenuRepository {
 	return &menuRepository{
 		db: db,
 	}
 }
 
-func (r *menuRepository) CreateMenuItem(item models.MenuItem) (*models.MenuItem, error) {
+func insertMenuItem(q querier, item models.MenuItem) (int, error) {
 	var id int
-	err := r.db.QueryRow(`
-        INSERT INTO menu_items (name, description, categories, allergens, price, available, size)
+	err := q.QueryRow(`
+        INSERT INTO menu_items (item_type, name, description, allergens, price, available, size)
         VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
-		item.Name, item.Description, pq.Array(item.Categories), pq.Array(item.Allergens), item.Price, item.Available, item.Size).
+		item.Type, item.Name, item.Description, pq.Array(item.Allergens), item.Price, item.Available, item.Size).
 		Scan(&id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create menu item: %v", err)
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("menu item %q already exists: %w", item.Name, cerrors.ErrExist)
+		}
+		return 0, fmt.Errorf("failed to create menu item: %v", err)
 	}
 
-	item.ID = id
-	slog.Info("Menu item created", "item_id", id, "name", item.Name)
-	return &item, nil
-}
-
-func (r *menuRepository) AddIngredientToMenuItem(menuItemID int, ingredient models.MenuItemIngredient) error {
-	_, err := r.db.Exec(`
-        INSERT INTO menu_item_ingredients (menu_item_id, ingredient_id, quantity, unit)
-        VALUES ($1, $2, $3, $4)`,
-		menuItemID, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit)
-	if err != nil {
-		return fmt.Errorf("failed to add ingredient to menu item: %v", err)
+	if err := setMenuItemCategories(q, id, item.CategoryIDs); err != nil {
+		return 0, err
 	}
-	return nil
+	return id, nil
 }
 
 func (r *menuRepository) GetIngredientsByMenuItemID(menuItemID int) ([]models.MenuItemIngredient, error) {
 	rows, err := r.db.Query(`
-        SELECT ingredient_id, quantity
+        SELECT ingredient_id, quantity, unit
         FROM menu_item_ingredients
         WHERE menu_item_id = $1`, menuItemID)
 	if err != nil {
@@ -72,7 +137,7 @@ func (r *menuRepository) GetIngredientsByMenuItemID(menuItemID int) ([]models.Me
 	var ingredients []models.MenuItemIngredient
 	for rows.Next() {
 		var ing models.MenuItemIngredient
-		if err := rows.Scan(&ing.IngredientID, &ing.Quantity); err != nil {
+		if err := rows.Scan(&ing.IngredientID, &ing.Quantity, &ing.Unit); err != nil {
 			return nil, fmt.Errorf("failed to scan ingredient: %v", err)
 		}
 		ingredients = append(ingredients, ing)
@@ -80,10 +145,73 @@ func (r *menuRepository) GetIngredientsByMenuItemID(menuItemID int) ([]models.Me
 	return ingredients, nil
 }
 
+// GetAllIngredients возвращает рецептуры всех позиций меню, сгруппированные по menu_item_id.
+func (r *menuRepository) GetAllIngredients() (map[int][]models.MenuItemIngredient, error) {
+	return queryAllIngredients(r.db)
+}
+
+func queryAllIngredients(q querier) (map[int][]models.MenuItemIngredient, error) {
+	rows, err := q.Query(`
+        SELECT menu_item_id, ingredient_id, quantity, unit
+        FROM menu_item_ingredients
+        ORDER BY menu_item_id, ingredient_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ingredients: %v", err)
+	}
+	defer rows.Close()
+
+	recipes := make(map[int][]models.MenuItemIngredient)
+	for rows.Next() {
+		var menuItemID int
+		var ing models.MenuItemIngredient
+		if err := rows.Scan(&menuItemID, &ing.IngredientID, &ing.Quantity, &ing.Unit); err != nil {
+			return nil, fmt.Errorf("failed to scan ingredient: %v", err)
+		}
+		recipes[menuItemID] = append(recipes[menuItemID], ing)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return recipes, nil
+}
+
+// setIngredients заменяет рецептуру позиции целиком.
+func setIngredients(q querier, menuItemID int, ingredients []models.MenuItemIngredient) error {
+	if _, err := q.Exec(`DELETE FROM menu_item_ingredients WHERE menu_item_id = $1`, menuItemID); err != nil {
+		return fmt.Errorf("failed to clear ingredients: %v", err)
+	}
+	for _, ing := range ingredients {
+		_, err := q.Exec(`
+            INSERT INTO menu_item_ingredients (menu_item_id, ingredient_id, quantity, unit)
+            VALUES ($1, $2, $3, $4)`,
+			menuItemID, ing.IngredientID, ing.Quantity, ing.Unit)
+		if err != nil {
+			return fmt.Errorf("failed to add ingredient to menu item: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *menuRepository) SetSoldOut(id int, soldOut bool) error {
+	_, err := r.db.Exec(`UPDATE menu_items SET sold_out = $1 WHERE id = $2`, soldOut, id)
+	if err != nil {
+		return fmt.Errorf("failed to update sold out flag: %v", err)
+	}
+	return nil
+}
+
 func (r *menuRepository) GetAllMenuItems() ([]models.MenuItem, error) {
-	rows, err := r.db.Query(`
-        SELECT id, name, description, categories, allergens, price, available, size
-        FROM menu_items`)
+	items, err := queryMenuItems(r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("GetAllMenuItems called", "count", len(items))
+	return items, nil
+}
+
+func queryMenuItems(q querier) ([]models.MenuItem, error) {
+	rows, err := q.Query(`SELECT ` + menuItemColumns + ` FROM menu_items mi ORDER BY mi.id`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query menu items: %v", err)
 	}
@@ -91,25 +219,20 @@ func (r *menuRepository) GetAllMenuItems() ([]models.MenuItem, error) {
 
 	var items []models.MenuItem
 	for rows.Next() {
-		var item models.MenuItem
-		err := rows.Scan(&item.ID, &item.Name, &item.Description, pq.Array(&item.Categories), pq.Array(&item.Allergens), &item.Price, &item.Available, &item.Size)
+		item, err := scanMenuItem(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan menu item: %v", err)
 		}
 		items = append(items, item)
 	}
-
-	slog.Info("GetAllMenuItems called", "count", len(items))
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
 	return items, nil
 }
 
 func (r *menuRepository) GetMenuItemByID(id int) (*models.MenuItem, error) {
-	var item models.MenuItem
-	err := r.db.QueryRow(`
-        SELECT id, name, description, categories, allergens, price, available, size
-        FROM menu_items
-        WHERE id = $1`, id).
-		Scan(&item.ID, &item.Name, &item.Description, pq.Array(&item.Categories), pq.Array(&item.Allergens), &item.Price, &item.Available, &item.Size)
+	item, err := scanMenuItem(r.db.QueryRow(`SELECT `+menuItemColumns+` FROM menu_items mi WHERE mi.id = $1`, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, cerrors.ErrMenuItemNotFound
@@ -121,61 +244,79 @@ func (r *menuRepository) GetMenuItemByID(id int) (*models.MenuItem, error) {
 	return &item, nil
 }
 
-func (r *menuRepository) UpdateMenuItem(id int, item models.MenuItem) (*models.MenuItem, error) {
-	result, err := r.db.Exec(`
-        UPDATE menu_items 
-        SET name = $1, description = $2, categories = $3, allergens = $4, price = $5, available = $6, size = $7
-        WHERE id = $8`,
-		item.Name, item.Description, pq.Array(item.Categories), pq.Array(item.Allergens), item.Price, item.Available, item.Size, id)
+// updateMenuItem вызывается внутри транзакции: строка блокируется до записи истории цен.
+func updateMenuItem(q querier, id int, item models.MenuItem) error {
+	// Старую цену читаем до обновления, иначе в историю попадёт новая
+	var oldPrice float64
+	err := q.QueryRow(`SELECT price FROM menu_items WHERE id = $1 FOR UPDATE`, id).Scan(&oldPrice)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update menu item: %v", err)
+		if err == sql.ErrNoRows {
+			return cerrors.ErrMenuItemNotFound
+		}
+		return fmt.Errorf("failed to get old price: %v", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	_, err = q.Exec(`
+        UPDATE menu_items 
+        SET name = $1, description = $2, allergens = $3, price = $4, available = $5, size = $6
+        WHERE id = $7`,
+		item.Name, item.Description, pq.Array(item.Allergens), item.Price, item.Available, item.Size, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check rows affected: %v", err)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("menu item %q already exists: %w", item.Name, cerrors.ErrExist)
+		}
+		return fmt.Errorf("failed to update menu item: %v", err)
 	}
-	if rowsAffected == 0 {
-		return nil, cerrors.ErrMenuItemNotFound
+
+	if err := setMenuItemCategories(q, id, item.CategoryIDs); err != nil {
+		return err
 	}
 
 	// Записываем историю изменения цены, если цена изменилась
-	var oldPrice float64
-	err = r.db.QueryRow(`SELECT price FROM menu_items WHERE id = $1`, id).Scan(&oldPrice)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get old price: %v", err)
-	}
 	if oldPrice != item.Price {
-		_, err = r.db.Exec(`
-            INSERT INTO price_history (menu_item_id, old_price, new_price, changed_at)
-            VALUES ($1, $2, $3, NOW())`,
-			id, oldPrice, item.Price)
-		if err != nil {
-			return nil, fmt.Errorf("failed to log price history: %v", err)
+		if err := logPriceChange(q, id, oldPrice, item.Price); err != nil {
+			return err
 		}
 	}
-
-	item.ID = id
-	slog.Info("Menu item updated", "item_id", id, "name", item.Name)
-	return &item, nil
+	return nil
 }
 
-func (r *menuRepository) DeleteMenuItem(id int) error {
+func deleteMenuItem(q querier, id int) error {
 	var count int
-	err := r.db.QueryRow(`SELECT COUNT(*) FROM order_items WHERE menu_item_id = $1`, id).Scan(&count)
+	err := q.QueryRow(`SELECT COUNT(*) FROM order_items WHERE menu_item_id = $1`, id).Scan(&count)
 	if err != nil {
 		return fmt.Errorf("failed to check for related order items: %v", err)
 	}
 
 	if count > 0 {
-		return fmt.Errorf("cannot delete menu item with ID %d because it is used in orders", id)
+		return fmt.Errorf("cannot delete menu item with ID %d because it is used in orders: %w", id, cerrors.ErrInUse)
+	}
+
+	// Компонент набора защищён внешним ключом: называем наборы вместо ошибки PostgreSQL
+	var bundles []string
+	err = q.QueryRow(`
+        SELECT COALESCE(array_agg(m.name ORDER BY m.name), '{}')
+        FROM bundle_components b
+        JOIN menu_items m ON m.id = b.bundle_id
+        WHERE b.component_id = $1`, id).Scan(pq.Array(&bundles))
+	if err != nil {
+		return fmt.Errorf("failed to check for bundles: %v", err)
+	}
+	if len(bundles) > 0 {
+		return fmt.Errorf("cannot delete menu item with ID %d because it is a component of bundles %s: %w",
+			id, strings.Join(bundles, ", "), cerrors.ErrInUse)
 	}
 
 	// Удаление меню
-	_, err = r.db.Exec(`DELETE FROM menu_items WHERE id = $1`, id)
+	_, err = q.Exec(`DELETE FROM menu_items WHERE id = $1`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete menu item: %v", err)
 	}
 
 	return nil
 }
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
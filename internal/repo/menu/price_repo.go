@@ -0,0 +1,168 @@
+package menu
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+func logPriceChange(q querier, menuItemID int, oldPrice, newPrice float64) error {
+	_, err := q.Exec(`
+        INSERT INTO price_history (menu_item_id, old_price, new_price, changed_at)
+        VALUES ($1, $2, $3, NOW())`,
+		menuItemID, oldPrice, newPrice)
+	if err != nil {
+		return fmt.Errorf("failed to log price history: %v", err)
+	}
+	return nil
+}
+
+func (r *menuRepository) GetPriceHistory(menuItemID int) ([]models.PriceHistory, error) {
+	rows, err := r.db.Query(`
+        SELECT id, menu_item_id, old_price, new_price, changed_at
+        FROM price_history
+        WHERE menu_item_id = $1
+        ORDER BY changed_at DESC, id DESC`, menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price history: %v", err)
+	}
+	defer rows.Close()
+
+	history := []models.PriceHistory{}
+	for rows.Next() {
+		var h models.PriceHistory
+		if err := rows.Scan(&h.ID, &h.MenuItemID, &h.OldPrice, &h.NewPrice, &h.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan price history: %v", err)
+		}
+		history = append(history, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return history, nil
+}
+
+func (r *menuRepository) CreateScheduledPriceChange(change models.ScheduledPriceChange) (*models.ScheduledPriceChange, error) {
+	err := r.db.QueryRow(`
+        INSERT INTO scheduled_price_changes (menu_item_id, new_price, effective_at)
+        VALUES ($1, $2, $3) RETURNING id, created_at`,
+		change.MenuItemID, change.NewPrice, change.EffectiveAt).
+		Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule price change: %v", err)
+	}
+	return &change, nil
+}
+
+func (r *menuRepository) GetScheduledPriceChanges(menuItemID int) ([]models.ScheduledPriceChange, error) {
+	rows, err := r.db.Query(`
+        SELECT id, menu_item_id, new_price, effective_at, applied_at, created_at
+        FROM scheduled_price_changes
+        WHERE menu_item_id = $1
+        ORDER BY effective_at, id`, menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduled price changes: %v", err)
+	}
+	defer rows.Close()
+
+	changes := []models.ScheduledPriceChange{}
+	for rows.Next() {
+		var c models.ScheduledPriceChange
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.MenuItemID, &c.NewPrice, &c.EffectiveAt, &appliedAt, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled price change: %v", err)
+		}
+		if appliedAt.Valid {
+			c.AppliedAt = &appliedAt.Time
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return changes, nil
+}
+
+func (r *menuRepository) DeleteScheduledPriceChange(menuItemID, changeID int) error {
+	result, err := r.db.Exec(`
+        DELETE FROM scheduled_price_changes
+        WHERE id = $1 AND menu_item_id = $2 AND applied_at IS NULL`, changeID, menuItemID)
+	if err != nil {
+		return fmt.Errorf("failed to delete scheduled price change: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return cerrors.ErrNotExist
+	}
+	return nil
+}
+
+// ApplyDuePriceChanges применяет все запланированные изменения цены, срок которых наступил,
+// в одной транзакции. Каждое проходит как правка меню (applyMenuChange): попадает
+// в price_history и сохраняется новой версией меню, чтобы откат и сравнение версий его видели.
+func (r *menuRepository) ApplyDuePriceChanges(now time.Time) ([]models.ScheduledPriceChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+        SELECT id, menu_item_id, new_price, effective_at, created_at
+        FROM scheduled_price_changes
+        WHERE applied_at IS NULL AND effective_at <= $1
+        ORDER BY effective_at, id
+        FOR UPDATE SKIP LOCKED`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due price changes: %v", err)
+	}
+
+	var due []models.ScheduledPriceChange
+	for rows.Next() {
+		var c models.ScheduledPriceChange
+		if err := rows.Scan(&c.ID, &c.MenuItemID, &c.NewPrice, &c.EffectiveAt, &c.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan due price change: %v", err)
+		}
+		due = append(due, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+
+	for i, c := range due {
+		item, err := scanMenuItem(tx.QueryRow(`SELECT `+menuItemColumns+` FROM menu_items mi WHERE mi.id = $1 FOR UPDATE`, c.MenuItemID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get menu item %d: %v", c.MenuItemID, err)
+		}
+
+		if item.Price != c.NewPrice {
+			note := fmt.Sprintf("scheduled price change %d: %q %.2f -> %.2f", c.ID, item.Name, item.Price, c.NewPrice)
+			item.Price = c.NewPrice
+			// Рецептура и состав набора (nil) не меняются
+			change := models.MenuDraftChange{Action: models.DraftActionUpdate, MenuItemID: &c.MenuItemID, Item: &item}
+			if _, _, err := applyMenuChange(tx, change, note); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := tx.Exec(`UPDATE scheduled_price_changes SET applied_at = $1 WHERE id = $2`, now, c.ID); err != nil {
+			return nil, fmt.Errorf("failed to mark price change as applied: %v", err)
+		}
+		appliedAt := now
+		due[i].AppliedAt = &appliedAt
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return due, nil
+}
@@ -0,0 +1,88 @@
+package menu
+
+import (
+	"fmt"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+
+	"github.com/lib/pq"
+)
+
+func (r *menuRepository) GetTranslations() (map[int][]models.MenuItemTranslation, error) {
+	rows, err := r.db.Query(`
+        SELECT menu_item_id, locale, name, description, allergens
+        FROM menu_item_translations
+        ORDER BY menu_item_id, locale`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query translations: %v", err)
+	}
+	defer rows.Close()
+
+	translations := make(map[int][]models.MenuItemTranslation)
+	for rows.Next() {
+		var t models.MenuItemTranslation
+		if err := rows.Scan(&t.MenuItemID, &t.Locale, &t.Name, &t.Description, pq.Array(&t.Allergens)); err != nil {
+			return nil, fmt.Errorf("failed to scan translation: %v", err)
+		}
+		translations[t.MenuItemID] = append(translations[t.MenuItemID], t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return translations, nil
+}
+
+func (r *menuRepository) GetTranslationsByMenuItemID(menuItemID int) ([]models.MenuItemTranslation, error) {
+	rows, err := r.db.Query(`
+        SELECT menu_item_id, locale, name, description, allergens
+        FROM menu_item_translations
+        WHERE menu_item_id = $1
+        ORDER BY locale`, menuItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query translations: %v", err)
+	}
+	defer rows.Close()
+
+	translations := []models.MenuItemTranslation{}
+	for rows.Next() {
+		var t models.MenuItemTranslation
+		if err := rows.Scan(&t.MenuItemID, &t.Locale, &t.Name, &t.Description, pq.Array(&t.Allergens)); err != nil {
+			return nil, fmt.Errorf("failed to scan translation: %v", err)
+		}
+		translations = append(translations, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return translations, nil
+}
+
+func (r *menuRepository) SetTranslation(t models.MenuItemTranslation) error {
+	_, err := r.db.Exec(`
+        INSERT INTO menu_item_translations (menu_item_id, locale, name, description, allergens)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (menu_item_id, locale)
+        DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, allergens = EXCLUDED.allergens`,
+		t.MenuItemID, t.Locale, t.Name, t.Description, pq.Array(t.Allergens))
+	if err != nil {
+		return fmt.Errorf("failed to save translation: %v", err)
+	}
+	return nil
+}
+
+func (r *menuRepository) DeleteTranslation(menuItemID int, locale string) error {
+	result, err := r.db.Exec(`DELETE FROM menu_item_translations WHERE menu_item_id = $1 AND locale = $2`, menuItemID, locale)
+	if err != nil {
+		return fmt.Errorf("failed to delete translation: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return cerrors.ErrNotExist
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+package menu
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+func (r *menuRepository) GetDraftChanges() ([]models.MenuDraftChange, error) {
+	return queryDraftChanges(r.db, false)
+}
+
+func queryDraftChanges(q querier, lock bool) ([]models.MenuDraftChange, error) {
+	query := `
+        SELECT id, action, menu_item_id, payload, created_at
+        FROM menu_draft_changes
+        ORDER BY id`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+
+	rows, err := q.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query draft changes: %v", err)
+	}
+	defer rows.Close()
+
+	changes := []models.MenuDraftChange{}
+	for rows.Next() {
+		var c models.MenuDraftChange
+		var menuItemID sql.NullInt64
+		var payload []byte
+		if err := rows.Scan(&c.ID, &c.Action, &menuItemID, &payload, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan draft change: %v", err)
+		}
+		if menuItemID.Valid {
+			id := int(menuItemID.Int64)
+			c.MenuItemID = &id
+		}
+		if payload != nil {
+			c.Item = &models.MenuItem{}
+			if err := json.Unmarshal(payload, c.Item); err != nil {
+				return nil, fmt.Errorf("failed to decode draft change %d: %v", c.ID, err)
+			}
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return changes, nil
+}
+
+func (r *menuRepository) CreateDraftChange(change models.MenuDraftChange) (*models.MenuDraftChange, error) {
+	var payload []byte
+	if change.Item != nil {
+		var err error
+		if payload, err = json.Marshal(change.Item); err != nil {
+			return nil, fmt.Errorf("failed to encode draft change: %v", err)
+		}
+	}
+
+	err := r.db.QueryRow(`
+        INSERT INTO menu_draft_changes (action, menu_item_id, payload)
+        VALUES ($1, $2, $3) RETURNING id, created_at`,
+		change.Action, change.MenuItemID, payload).
+		Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage draft change: %v", err)
+	}
+	return &change, nil
+}
+
+func (r *menuRepository) DeleteDraftChange(id int) error {
+	result, err := r.db.Exec(`DELETE FROM menu_draft_changes WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete draft change: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return cerrors.ErrNotExist
+	}
+	return nil
+}
+
+func (r *menuRepository) DiscardDraft() error {
+	if _, err := r.db.Exec(`DELETE FROM menu_draft_changes`); err != nil {
+		return fmt.Errorf("failed to discard draft: %v", err)
+	}
+	return nil
+}
+
+// PublishDraft применяет все изменения черновика в одной транзакции и сохраняет
+// получившееся меню как новую версию. Если хоть одно изменение не применилось,
+// живое меню остаётся прежним.
+func (r *menuRepository) PublishDraft(note string) (*models.MenuVersion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	changes, err := queryDraftChanges(tx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range changes {
+		if _, err := applyDraftChange(tx, c); err != nil {
+			return nil, fmt.Errorf("draft change %d (%s): %w", c.ID, c.Action, err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM menu_draft_changes`); err != nil {
+		return nil, fmt.Errorf("failed to clear draft: %v", err)
+	}
+
+	version, err := saveVersion(tx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	slog.Info("Menu draft published", "version", version.ID, "changes", len(changes))
+	return version, nil
+}
+
+// ImportMenu применяет импортированные позиции одной транзакцией, минуя черновик,
+// и сохраняет результат как новую версию меню.
+func (r *menuRepository) ImportMenu(changes []models.MenuDraftChange, note string) (*models.MenuVersion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for i, c := range changes {
+		if _, err := applyDraftChange(tx, c); err != nil {
+			return nil, fmt.Errorf("import change %d (%s %q): %w", i+1, c.Action, c.Item.Name, err)
+		}
+	}
+
+	version, err := saveVersion(tx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	slog.Info("Menu imported", "version", version.ID, "changes", len(changes))
+	return version, nil
+}
+
+// ApplyMenuChange применяет правку живого меню (POST, PUT и DELETE /menu) и в той же
+// транзакции сохраняет меню как новую версию, чтобы откат к версии не терял
+// прямые правки. Возвращает id позиции.
+func (r *menuRepository) ApplyMenuChange(change models.MenuDraftChange, note string) (int, *models.MenuVersion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	id, version, err := applyMenuChange(tx, change, note)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	slog.Info("Menu item changed", "action", change.Action, "item_id", id, "version", version.ID)
+	return id, version, nil
+}
+
+// applyMenuChange — ApplyMenuChange внутри уже открытой транзакции.
+func applyMenuChange(tx *sql.Tx, change models.MenuDraftChange, note string) (int, *models.MenuVersion, error) {
+	id, err := applyDraftChange(tx, change)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	version, err := saveVersion(tx, note)
+	if err != nil {
+		return 0, nil, err
+	}
+	return id, version, nil
+}
+
+// applyDraftChange применяет одно изменение и возвращает id затронутой позиции.
+func applyDraftChange(q querier, c models.MenuDraftChange) (int, error) {
+	switch c.Action {
+	case models.DraftActionCreate:
+		id, err := insertMenuItem(q, *c.Item)
+		if err != nil {
+			return 0, err
+		}
+		if err := setIngredients(q, id, c.Item.Ingredients); err != nil {
+			return 0, err
+		}
+		return id, setBundleComponents(q, id, c.Item.Components)
+	case models.DraftActionUpdate:
+		id := *c.MenuItemID
+		if err := updateMenuItem(q, id, *c.Item); err != nil {
+			return 0, err
+		}
+		// nil — состав не менялся, пустой список — очистить
+		if c.Item.Ingredients != nil {
+			if err := setIngredients(q, id, c.Item.Ingredients); err != nil {
+				return 0, err
+			}
+		}
+		if c.Item.Components != nil {
+			return id, setBundleComponents(q, id, c.Item.Components)
+		}
+		return id, nil
+	case models.DraftActionDelete:
+		return *c.MenuItemID, deleteMenuItem(q, *c.MenuItemID)
+	default:
+		return 0, fmt.Errorf("unknown draft action %q", c.Action)
+	}
+}
+
+// snapshotMenu собирает меню вместе с рецептурами и составом наборов.
+func snapshotMenu(q querier) ([]models.MenuItem, error) {
+	items, err := queryMenuItems(q)
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := queryAllIngredients(q)
+	if err != nil {
+		return nil, err
+	}
+	components, err := queryBundleComponents(q)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Ingredients = recipes[items[i].ID]
+		items[i].Components = components[items[i].ID]
+	}
+	return items, nil
+}
+
+func saveVersion(q querier, note string) (*models.MenuVersion, error) {
+	items, err := snapshotMenu(q)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode menu snapshot: %v", err)
+	}
+
+	version := models.MenuVersion{Note: note, ItemCount: len(items), Items: items}
+	err = q.QueryRow(`
+        INSERT INTO menu_versions (note, snapshot)
+        VALUES ($1, $2) RETURNING id, published_at`, note, snapshot).
+		Scan(&version.ID, &version.PublishedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save menu version: %v", err)
+	}
+	return &version, nil
+}
+
+func (r *menuRepository) GetMenuVersions() ([]models.MenuVersion, error) {
+	rows, err := r.db.Query(`
+        SELECT id, note, published_at, jsonb_array_length(snapshot)
+        FROM menu_versions
+        ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query menu versions: %v", err)
+	}
+	defer rows.Close()
+
+	versions := []models.MenuVersion{}
+	for rows.Next() {
+		var v models.MenuVersion
+		if err := rows.Scan(&v.ID, &v.Note, &v.PublishedAt, &v.ItemCount); err != nil {
+			return nil, fmt.Errorf("failed to scan menu version: %v", err)
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return versions, nil
+}
+
+func (r *menuRepository) GetMenuVersion(id int) (*models.MenuVersion, error) {
+	var v models.MenuVersion
+	var snapshot []byte
+	err := r.db.QueryRow(`
+        SELECT id, note, published_at, snapshot
+        FROM menu_versions
+        WHERE id = $1`, id).
+		Scan(&v.ID, &v.Note, &v.PublishedAt, &snapshot)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, cerrors.ErrNotExist
+		}
+		return nil, fmt.Errorf("failed to query menu version: %v", err)
+	}
+
+	if err := json.Unmarshal(snapshot, &v.Items); err != nil {
+		return nil, fmt.Errorf("failed to decode menu snapshot: %v", err)
+	}
+	v.ItemCount = len(v.Items)
+	return &v, nil
+}
+
+// RollbackToVersion возвращает живое меню к состоянию версии и публикует его как новую версию.
+// Позиции, появившиеся позже, скрываются (available = false), а не удаляются: на них могут ссылаться заказы.
+// Удалённые с тех пор позиции восстановить нельзя, они пропускаются.
+func (r *menuRepository) RollbackToVersion(id int, note string) (*models.MenuVersion, error) {
+	target, err := r.GetMenuVersion(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	current, err := queryMenuItems(tx)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[int]bool, len(current))
+	for _, item := range current {
+		live[item.ID] = true
+	}
+
+	var existingCategories []int
+	rows, err := tx.Query(`SELECT id FROM categories`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %v", err)
+	}
+	for rows.Next() {
+		var categoryID int
+		if err := rows.Scan(&categoryID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan category: %v", err)
+		}
+		existingCategories = append(existingCategories, categoryID)
+	}
+	rows.Close()
+	categories := make(map[int]bool, len(existingCategories))
+	for _, categoryID := range existingCategories {
+		categories[categoryID] = true
+	}
+
+	restored := make(map[int]bool, len(target.Items))
+	for _, item := range target.Items {
+		if !live[item.ID] {
+			slog.Warn("Menu item from version no longer exists, skipping", "version", id, "item_id", item.ID)
+			continue
+		}
+		restored[item.ID] = true
+
+		var categoryIDs []int
+		for _, categoryID := range item.CategoryIDs {
+			if categories[categoryID] {
+				categoryIDs = append(categoryIDs, categoryID)
+			}
+		}
+		item.CategoryIDs = categoryIDs
+
+		if err := updateMenuItem(tx, item.ID, item); err != nil {
+			return nil, err
+		}
+		if err := setIngredients(tx, item.ID, item.Ingredients); err != nil {
+			return nil, err
+		}
+	}
+
+	// Составы наборов восстанавливаем после всех позиций, чтобы компоненты уже существовали
+	for _, item := range target.Items {
+		if !restored[item.ID] {
+			continue
+		}
+		var components []models.BundleComponent
+		for _, c := range item.Components {
+			if live[c.MenuItemID] {
+				components = append(components, c)
+			}
+		}
+		if err := setBundleComponents(tx, item.ID, components); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item := range current {
+		if !restored[item.ID] && item.Available {
+			if _, err := tx.Exec(`UPDATE menu_items SET available = FALSE WHERE id = $1`, item.ID); err != nil {
+				return nil, fmt.Errorf("failed to hide menu item: %v", err)
+			}
+		}
+	}
+
+	version, err := saveVersion(tx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	slog.Info("Menu rolled back", "to_version", id, "new_version", version.ID)
+	return version, nil
+}
@@ -3,16 +3,18 @@ package order
 import (
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"frappuccino/internal/models"
+	"frappuccino/internal/repo/lot"
 
 	_ "github.com/lib/pq"
 )
 
 type OrderRepository interface {
-	CreateOrder(data models.Order) error
+	CreateOrder(data models.Order, usage []models.IngredientUsage) error
 	GetAllOrders() ([]models.Order, error)
 	GetOrderByID(id int) (models.Order, error)
 	UpdateOrder(id int, data models.Order) error
@@ -21,6 +23,8 @@ type OrderRepository interface {
 	GetPopularItems() ([]models.PopularItem, error)
 	GetNumberOfOrderedItems(startDate, endDate string) (map[string]int, error)
 	GetCustomerNameByID(customerID int) (string, error)
+	GetSubstitutions(startDate, endDate string) ([]models.SubstitutionRecord, error)
+	GetSalesByWeekdayHour(since, until time.Time) ([]models.SalesSlot, error)
 	BatchProcessOrders(orders []models.Order) (*models.BatchOrderResponse, error)
 }
 
@@ -34,83 +38,95 @@ func New(db *sql.DB) OrderRepository {
 	}
 }
 
-func (r *orderRepository) CreateOrder(data models.Order) error {
+// CreateOrder сохраняет заказ и в той же транзакции списывает ингредиенты по usage,
+// который сервис уже рассчитал по рецептурам (включая компоненты комбо-наборов).
+func (r *orderRepository) CreateOrder(data models.Order, usage []models.IngredientUsage) error {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %v", err)
 	}
 	defer tx.Rollback()
 
+	if data.LocationID == 0 {
+		data.LocationID = models.DefaultLocationID
+	}
+
 	// Вставка заказа в таблицу orders
 	var orderID int
 	err = tx.QueryRow(`
-        INSERT INTO orders (customer_id, status, total_amount, payment_method, special_instructions, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
-		data.CustomerID, "open", data.TotalAmount, data.PaymentMethod, data.SpecialInstructions, time.Now()).
+        INSERT INTO orders (customer_id, status, total_amount, payment_method, special_instructions, location_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		data.CustomerID, "open", data.TotalAmount, data.PaymentMethod, data.SpecialInstructions, data.LocationID, time.Now()).
 		Scan(&orderID)
 	if err != nil {
 		return fmt.Errorf("failed to insert order: %v", err)
 	}
 
-	// Вставка элементов заказа и обновление инвентаря
+	// Вставка элементов заказа; цену уже рассчитал сервис с учётом правил ценообразования
 	for _, item := range data.Items {
-		// Получаем цену из menu_items
-		var price float64
-		err = tx.QueryRow(`SELECT price FROM menu_items WHERE id = $1`, item.MenuItemID).Scan(&price)
-		if err != nil {
-			return fmt.Errorf("failed to get menu item price: %v", err)
-		}
-
-		// Вставка элемента заказа
 		var itemID int
 		err = tx.QueryRow(`
             INSERT INTO order_items (order_id, menu_item_id, quantity, price, customizations)
             VALUES ($1, $2, $3, $4, $5) RETURNING id`,
-			orderID, item.MenuItemID, item.Quantity, price, item.Customizations).Scan(&itemID)
+			orderID, item.MenuItemID, item.Quantity, item.Price, item.Customizations).
+			Scan(&itemID)
 		if err != nil {
 			return fmt.Errorf("failed to insert order item: %v", err)
 		}
 
-		// Проверка и обновление инвентаря
-		rows, err := tx.Query(`
-            SELECT ingredient_id, quantity FROM menu_item_ingredients WHERE menu_item_id = $1`, item.MenuItemID)
-		if err != nil {
-			return fmt.Errorf("failed to get ingredients: %v", err)
+		for _, sub := range item.Substitutions {
+			_, err = tx.Exec(`
+                INSERT INTO order_item_substitutions (order_item_id, ingredient_id, substitute_id, original_quantity, quantity)
+                VALUES ($1, $2, $3, $4, $5)`,
+				itemID, sub.IngredientID, sub.SubstituteID, sub.OriginalQuantity, sub.Quantity)
+			if err != nil {
+				return fmt.Errorf("failed to insert substitution: %v", err)
+			}
 		}
-		defer rows.Close()
+	}
 
-		for rows.Next() {
-			var ingredientID int
-			var requiredQty float64
-			if err := rows.Scan(&ingredientID, &requiredQty); err != nil {
-				return fmt.Errorf("failed to scan ingredient: %v", err)
-			}
+	// Списание ингредиентов: суммируем по ингредиенту и блокируем строки в порядке id,
+	// чтобы параллельные заказы не взаимоблокировались
+	required := make(map[int]float64)
+	var ingredientIDs []int
+	for _, u := range usage {
+		if _, exists := required[u.IngredientID]; !exists {
+			ingredientIDs = append(ingredientIDs, u.IngredientID)
+		}
+		required[u.IngredientID] += u.Quantity
+	}
+	sort.Ints(ingredientIDs)
 
-			var stock float64
-			err = tx.QueryRow(`SELECT stock FROM inventory WHERE id = $1 FOR UPDATE`, ingredientID).Scan(&stock)
-			if err != nil {
-				return fmt.Errorf("failed to check inventory: %v", err)
-			}
+	for _, ingredientID := range ingredientIDs {
+		totalRequired := required[ingredientID]
 
-			totalRequired := requiredQty * float64(item.Quantity)
-			if stock < totalRequired {
-				return fmt.Errorf("insufficient stock for ingredient %d", ingredientID)
-			}
+		var stock float64
+		err = tx.QueryRow(`SELECT stock FROM inventory WHERE id = $1 FOR UPDATE`, ingredientID).Scan(&stock)
+		if err != nil {
+			return fmt.Errorf("failed to check inventory: %v", err)
+		}
 
-			_, err = tx.Exec(`
-                UPDATE inventory SET stock = stock - $1 WHERE id = $2`,
-				totalRequired, ingredientID)
-			if err != nil {
-				return fmt.Errorf("failed to update inventory: %v", err)
-			}
+		if stock < totalRequired {
+			return fmt.Errorf("insufficient stock for ingredient %d", ingredientID)
+		}
 
-			_, err = tx.Exec(`
-                INSERT INTO inventory_transactions (ingredient_id, change_amount, transaction_type, occurred_at)
-                VALUES ($1, $2, 'use', NOW())`,
-				ingredientID, -totalRequired)
-			if err != nil {
-				return fmt.Errorf("failed to log inventory transaction: %v", err)
-			}
+		_, err = tx.Exec(`
+            UPDATE inventory SET stock = stock - $1 WHERE id = $2`,
+			totalRequired, ingredientID)
+		if err != nil {
+			return fmt.Errorf("failed to update inventory: %v", err)
+		}
+		// Списание со склада кафе, где принят заказ; просроченные партии в заказы не идут
+		if _, err := lot.Consume(tx, ingredientID, data.LocationID, totalRequired, true); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+            INSERT INTO inventory_transactions (ingredient_id, change_amount, transaction_type, note, order_id, location_id, occurred_at)
+            VALUES ($1, $2, 'use', $3, $4, $5, NOW())`,
+			ingredientID, -totalRequired, fmt.Sprintf("order %d", orderID), orderID, data.LocationID)
+		if err != nil {
+			return fmt.Errorf("failed to log inventory transaction: %v", err)
 		}
 	}
 
@@ -145,7 +161,7 @@ func (r *orderRepository) GetCustomerNameByID(customerID int) (string, error) {
 
 func (r *orderRepository) GetAllOrders() ([]models.Order, error) {
 	rows, err := r.db.Query(`
-        SELECT o.id, o.customer_id, o.status, o.total_amount, o.payment_method, o.special_instructions, o.created_at, o.updated_at,
+        SELECT o.id, o.customer_id, o.status, o.total_amount, o.payment_method, o.special_instructions, o.location_id, o.created_at, o.updated_at,
                oi.id AS item_id, oi.menu_item_id, oi.quantity, oi.price, oi.customizations
         FROM orders o
         LEFT JOIN order_items oi ON o.id = oi.order_id`)
@@ -160,7 +176,7 @@ func (r *orderRepository) GetAllOrders() ([]models.Order, error) {
 		var item models.OrderItem
 		var itemID sql.NullInt64
 
-		err := rows.Scan(&o.ID, &o.CustomerID, &o.Status, &o.TotalAmount, &o.PaymentMethod, &o.SpecialInstructions, &o.CreatedAt, &o.UpdatedAt,
+		err := rows.Scan(&o.ID, &o.CustomerID, &o.Status, &o.TotalAmount, &o.PaymentMethod, &o.SpecialInstructions, &o.LocationID, &o.CreatedAt, &o.UpdatedAt,
 			&itemID, &item.MenuItemID, &item.Quantity, &item.Price, &item.Customizations)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan order: %v", err)
@@ -176,8 +192,14 @@ func (r *orderRepository) GetAllOrders() ([]models.Order, error) {
 		}
 	}
 
+	substitutions, err := r.querySubstitutions(``)
+	if err != nil {
+		return nil, err
+	}
+
 	var orders []models.Order
 	for _, order := range ordersMap {
+		attachSubstitutions(order, substitutions)
 		orders = append(orders, *order)
 	}
 	return orders, nil
@@ -185,7 +207,7 @@ func (r *orderRepository) GetAllOrders() ([]models.Order, error) {
 
 func (r *orderRepository) GetOrderByID(id int) (models.Order, error) {
 	rows, err := r.db.Query(`
-        SELECT o.id, o.customer_id, o.status, o.total_amount, o.payment_method, o.special_instructions, o.created_at, o.updated_at,
+        SELECT o.id, o.customer_id, o.status, o.total_amount, o.payment_method, o.special_instructions, o.location_id, o.created_at, o.updated_at,
                oi.id AS item_id, oi.menu_item_id, oi.quantity, oi.price, oi.customizations
         FROM orders o
         LEFT JOIN order_items oi ON o.id = oi.order_id
@@ -202,11 +224,11 @@ func (r *orderRepository) GetOrderByID(id int) (models.Order, error) {
 		var itemID sql.NullInt64
 
 		if first {
-			err = rows.Scan(&order.ID, &order.CustomerID, &order.Status, &order.TotalAmount, &order.PaymentMethod, &order.SpecialInstructions, &order.CreatedAt, &order.UpdatedAt,
+			err = rows.Scan(&order.ID, &order.CustomerID, &order.Status, &order.TotalAmount, &order.PaymentMethod, &order.SpecialInstructions, &order.LocationID, &order.CreatedAt, &order.UpdatedAt,
 				&itemID, &item.MenuItemID, &item.Quantity, &item.Price, &item.Customizations)
 			first = false
 		} else {
-			err = rows.Scan(new(int), new(int), new(string), new(float64), new(string), new(string), new(time.Time), new(time.Time),
+			err = rows.Scan(new(int), new(int), new(string), new(float64), new(string), new(string), new(int), new(time.Time), new(time.Time),
 				&itemID, &item.MenuItemID, &item.Quantity, &item.Price, &item.Customizations)
 		}
 		if err != nil {
@@ -220,9 +242,100 @@ func (r *orderRepository) GetOrderByID(id int) (models.Order, error) {
 	if first {
 		return models.Order{}, fmt.Errorf("order with ID %d not found", id)
 	}
+
+	substitutions, err := r.querySubstitutions(`WHERE oi.order_id = $1`, id)
+	if err != nil {
+		return models.Order{}, err
+	}
+	attachSubstitutions(&order, substitutions)
 	return order, nil
 }
 
+// querySubstitutions возвращает замены ингредиентов, сгруппированные по id позиции заказа.
+func (r *orderRepository) querySubstitutions(where string, args ...any) (map[int][]models.OrderItemSubstitution, error) {
+	rows, err := r.db.Query(`
+        SELECT s.order_item_id, COALESCE(s.ingredient_id, 0), COALESCE(i.name, ''),
+               COALESCE(s.substitute_id, 0), COALESCE(sub.name, ''), s.original_quantity, s.quantity
+        FROM order_item_substitutions s
+        JOIN order_items oi ON oi.id = s.order_item_id
+        LEFT JOIN inventory i ON i.id = s.ingredient_id
+        LEFT JOIN inventory sub ON sub.id = s.substitute_id
+        `+where+`
+        ORDER BY s.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query substitutions: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int][]models.OrderItemSubstitution)
+	for rows.Next() {
+		var itemID int
+		var sub models.OrderItemSubstitution
+		if err := rows.Scan(&itemID, &sub.IngredientID, &sub.IngredientName, &sub.SubstituteID, &sub.SubstituteName,
+			&sub.OriginalQuantity, &sub.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan substitution: %v", err)
+		}
+		result[itemID] = append(result[itemID], sub)
+	}
+	return result, rows.Err()
+}
+
+func attachSubstitutions(order *models.Order, substitutions map[int][]models.OrderItemSubstitution) {
+	for i := range order.Items {
+		order.Items[i].Substitutions = substitutions[order.Items[i].ID]
+	}
+}
+
+// GetSubstitutions — отчёт о заменах ингредиентов за период, новые сверху.
+func (r *orderRepository) GetSubstitutions(startDate, endDate string) ([]models.SubstitutionRecord, error) {
+	queryStr := `
+        SELECT o.id, oi.menu_item_id, m.name, o.created_at,
+               COALESCE(s.ingredient_id, 0), COALESCE(i.name, ''),
+               COALESCE(s.substitute_id, 0), COALESCE(sub.name, ''), s.original_quantity, s.quantity
+        FROM order_item_substitutions s
+        JOIN order_items oi ON oi.id = s.order_item_id
+        JOIN orders o ON o.id = oi.order_id
+        JOIN menu_items m ON m.id = oi.menu_item_id
+        LEFT JOIN inventory i ON i.id = s.ingredient_id
+        LEFT JOIN inventory sub ON sub.id = s.substitute_id`
+
+	var conditions []string
+	var args []any
+	if startDate != "" {
+		args = append(args, startDate)
+		conditions = append(conditions, fmt.Sprintf("o.created_at >= $%d", len(args)))
+	}
+	if endDate != "" {
+		args = append(args, endDate)
+		conditions = append(conditions, fmt.Sprintf("o.created_at <= $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		queryStr += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	queryStr += " ORDER BY o.created_at DESC, s.id"
+
+	rows, err := r.db.Query(queryStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query substitutions: %w", err)
+	}
+	defer rows.Close()
+
+	records := []models.SubstitutionRecord{}
+	for rows.Next() {
+		var rec models.SubstitutionRecord
+		if err := rows.Scan(&rec.OrderID, &rec.MenuItemID, &rec.MenuItemName, &rec.CreatedAt,
+			&rec.IngredientID, &rec.IngredientName, &rec.SubstituteID, &rec.SubstituteName,
+			&rec.OriginalQuantity, &rec.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan substitution: %w", err)
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+	return records, nil
+}
+
 func (r *orderRepository) UpdateOrder(id int, data models.Order) error {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -295,11 +408,12 @@ func (r *orderRepository) CloseOrder(id int) error {
 }
 
 func (r *orderRepository) GetPopularItems() ([]models.PopularItem, error) {
+	// order_item_sales раскладывает комбо-наборы на компоненты с долей выручки
 	rows, err := r.db.Query(`
-        SELECT oi.menu_item_id, mi.name, SUM(oi.quantity) AS popularity
-        FROM order_items oi
-        JOIN menu_items mi ON oi.menu_item_id = mi.id
-        GROUP BY oi.menu_item_id, mi.name
+        SELECT s.menu_item_id, mi.name, SUM(s.quantity) AS popularity, ROUND(SUM(s.revenue), 2) AS revenue
+        FROM order_item_sales s
+        JOIN menu_items mi ON s.menu_item_id = mi.id
+        GROUP BY s.menu_item_id, mi.name
         ORDER BY popularity DESC`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query popular items: %v", err)
@@ -309,7 +423,7 @@ func (r *orderRepository) GetPopularItems() ([]models.PopularItem, error) {
 	var popularItems []models.PopularItem
 	for rows.Next() {
 		var item models.PopularItem
-		if err := rows.Scan(&item.MenuItemID, &item.Name, &item.Popularity); err != nil {
+		if err := rows.Scan(&item.MenuItemID, &item.Name, &item.Popularity, &item.Revenue); err != nil {
 			return nil, fmt.Errorf("failed to scan popular item: %v", err)
 		}
 		popularItems = append(popularItems, item)
@@ -320,26 +434,28 @@ func (r *orderRepository) GetPopularItems() ([]models.PopularItem, error) {
 func (r *orderRepository) GetNumberOfOrderedItems(startDate, endDate string) (map[string]int, error) {
 	queryStr := `
     SELECT m.name AS item_name, COUNT(*)
-	FROM order_items oi
+	FROM order_item_sales oi
 	JOIN menu_items m ON oi.menu_item_id = m.id
-	JOIN orders o ON oi.order_id = o.id
-	GROUP BY m.name;
-	`
+	JOIN orders o ON oi.order_id = o.id`
 
 	// условия фильтрации по дате
 	var conditions []string
+	var args []any
 	if startDate != "" {
-		conditions = append(conditions, fmt.Sprintf("o.created_at >= '%s'", startDate))
+		args = append(args, startDate)
+		conditions = append(conditions, fmt.Sprintf("o.created_at >= $%d", len(args)))
 	}
 	if endDate != "" {
-		conditions = append(conditions, fmt.Sprintf("o.created_at <= '%s'", endDate))
+		args = append(args, endDate)
+		conditions = append(conditions, fmt.Sprintf("o.created_at <= $%d", len(args)))
 	}
 
 	if len(conditions) > 0 {
 		queryStr += " WHERE " + strings.Join(conditions, " AND ")
 	}
+	queryStr += " GROUP BY m.name"
 
-	rows, err := r.db.Query(queryStr)
+	rows, err := r.db.Query(queryStr, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -462,3 +578,44 @@ func countAcceptedOrders(orders []models.ProcessedOrder) int {
 	}
 	return acceptedCount
 }
+
+// GetSalesByWeekdayHour суммирует продажи позиций за [since, until) по дню недели
+// и часу заказа. Комбо-наборы разложены на компоненты, отменённые заказы не считаются.
+// День и час берутся в часовом поясе since, а не в поясе сессии базы: прогноз
+// раскладывает их по тем же дням и часам. База группирует по 15 минут, чтобы
+// часы сходились и в поясах со сдвигом на полчаса.
+func (r *orderRepository) GetSalesByWeekdayHour(since, until time.Time) ([]models.SalesSlot, error) {
+	rows, err := r.db.Query(`
+        SELECT s.menu_item_id, date_bin('15 minutes', o.created_at, TIMESTAMPTZ '2000-01-01 00:00:00+00'), SUM(s.quantity)
+        FROM order_item_sales s
+        JOIN orders o ON o.id = s.order_id
+        WHERE o.created_at >= $1 AND o.created_at < $2 AND o.status <> 'cancelled'
+        GROUP BY 1, 2`, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sales history: %v", err)
+	}
+	defer rows.Close()
+
+	slots := make(map[models.SalesSlot]float64)
+	for rows.Next() {
+		var slot models.SalesSlot
+		var bin time.Time
+		var quantity float64
+		if err := rows.Scan(&slot.MenuItemID, &bin, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan sales history: %v", err)
+		}
+		bin = bin.In(since.Location())
+		slot.Weekday, slot.Hour = bin.Weekday(), bin.Hour()
+		slots[slot] += quantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+
+	result := make([]models.SalesSlot, 0, len(slots))
+	for slot, quantity := range slots {
+		slot.Quantity = quantity
+		result = append(result, slot)
+	}
+	return result, nil
+}
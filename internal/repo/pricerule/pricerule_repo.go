@@ -0,0 +1,145 @@
+package pricerule
+
+import (
+	"database/sql"
+	"fmt"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+
+	"github.com/lib/pq"
+)
+
+type PriceRuleRepository interface {
+	GetAll() ([]models.PriceRule, error)
+	GetByID(id int) (models.PriceRule, error)
+	Create(rule models.PriceRule) (*models.PriceRule, error)
+	Update(id int, rule models.PriceRule) error
+	Delete(id int) error
+}
+
+type priceRuleRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) PriceRuleRepository {
+	return &priceRuleRepository{
+		db: db,
+	}
+}
+
+const selectRule = `
+        SELECT id, name, menu_item_id, category, discount_percent, days_of_week,
+               TO_CHAR(start_time, 'HH24:MI'), TO_CHAR(end_time, 'HH24:MI'), active
+        FROM price_rules`
+
+func scanRule(row interface{ Scan(...any) error }) (models.PriceRule, error) {
+	var rule models.PriceRule
+	var menuItemID sql.NullInt64
+	var days pq.Int64Array
+	err := row.Scan(&rule.ID, &rule.Name, &menuItemID, &rule.Category, &rule.DiscountPercent, &days,
+		&rule.StartTime, &rule.EndTime, &rule.Active)
+	if err != nil {
+		return models.PriceRule{}, err
+	}
+	if menuItemID.Valid {
+		id := int(menuItemID.Int64)
+		rule.MenuItemID = &id
+	}
+	rule.DaysOfWeek = make([]int, 0, len(days))
+	for _, d := range days {
+		rule.DaysOfWeek = append(rule.DaysOfWeek, int(d))
+	}
+	return rule, nil
+}
+
+func (r *priceRuleRepository) GetAll() ([]models.PriceRule, error) {
+	rows, err := r.db.Query(selectRule + ` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price rules: %v", err)
+	}
+	defer rows.Close()
+
+	rules := []models.PriceRule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price rule: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+	return rules, nil
+}
+
+func (r *priceRuleRepository) GetByID(id int) (models.PriceRule, error) {
+	rule, err := scanRule(r.db.QueryRow(selectRule+` WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.PriceRule{}, cerrors.ErrNotExist
+		}
+		return models.PriceRule{}, fmt.Errorf("failed to query price rule: %v", err)
+	}
+	return rule, nil
+}
+
+func (r *priceRuleRepository) Create(rule models.PriceRule) (*models.PriceRule, error) {
+	err := r.db.QueryRow(`
+        INSERT INTO price_rules (name, menu_item_id, category, discount_percent, days_of_week, start_time, end_time, active)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		rule.Name, rule.MenuItemID, rule.Category, rule.DiscountPercent, pq.Array(toInt64(rule.DaysOfWeek)),
+		rule.StartTime, rule.EndTime, rule.Active).
+		Scan(&rule.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create price rule: %v", err)
+	}
+	return &rule, nil
+}
+
+func (r *priceRuleRepository) Update(id int, rule models.PriceRule) error {
+	result, err := r.db.Exec(`
+        UPDATE price_rules
+        SET name = $1, menu_item_id = $2, category = $3, discount_percent = $4, days_of_week = $5,
+            start_time = $6, end_time = $7, active = $8
+        WHERE id = $9`,
+		rule.Name, rule.MenuItemID, rule.Category, rule.DiscountPercent, pq.Array(toInt64(rule.DaysOfWeek)),
+		rule.StartTime, rule.EndTime, rule.Active, id)
+	if err != nil {
+		return fmt.Errorf("failed to update price rule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return cerrors.ErrNotExist
+	}
+	return nil
+}
+
+func (r *priceRuleRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM price_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete price rule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return cerrors.ErrNotExist
+	}
+	return nil
+}
+
+func toInt64(values []int) []int64 {
+	result := make([]int64, 0, len(values))
+	for _, v := range values {
+		result = append(result, int64(v))
+	}
+	return result
+}
@@ -0,0 +1,421 @@
+package supplier
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"frappuccino/internal/models"
+	"frappuccino/internal/repo/lot"
+	"frappuccino/pkg/cerrors"
+)
+
+const selectPurchaseOrder = `
+        SELECT po.id, po.supplier_id, s.name, po.status, po.note, po.location_id, po.created_at, po.updated_at, po.expected_at,
+               COALESCE((SELECT SUM(l.quantity * l.unit_price) FROM purchase_order_lines l WHERE l.purchase_order_id = po.id), 0)
+        FROM purchase_orders po
+        JOIN suppliers s ON s.id = po.supplier_id`
+
+func scanPurchaseOrder(row interface{ Scan(...any) error }) (models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	var expectedAt sql.NullTime
+	err := row.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Note, &po.LocationID, &po.CreatedAt, &po.UpdatedAt, &expectedAt, &po.Total)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	if expectedAt.Valid {
+		po.ExpectedAt = &expectedAt.Time
+	}
+	return po, nil
+}
+
+// purchaseOrderLines возвращает строки заказов; orderID = 0 — всех заказов.
+func (r *supplierRepository) purchaseOrderLines(orderID int) (map[int][]models.PurchaseOrderLine, error) {
+	query := `
+        SELECT l.purchase_order_id, l.id, l.ingredient_id, i.name, i.unit, l.quantity, l.received_quantity, l.unit_price
+        FROM purchase_order_lines l
+        JOIN inventory i ON i.id = l.ingredient_id`
+	var args []any
+	if orderID != 0 {
+		query += ` WHERE l.purchase_order_id = $1`
+		args = append(args, orderID)
+	}
+	rows, err := r.db.Query(query+` ORDER BY l.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purchase order lines: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int][]models.PurchaseOrderLine)
+	for rows.Next() {
+		var id int
+		var l models.PurchaseOrderLine
+		if err := rows.Scan(&id, &l.ID, &l.IngredientID, &l.Name, &l.Unit, &l.Quantity, &l.ReceivedQuantity, &l.UnitPrice); err != nil {
+			return nil, fmt.Errorf("failed to scan purchase order line: %v", err)
+		}
+		result[id] = append(result[id], l)
+	}
+	return result, rows.Err()
+}
+
+func (r *supplierRepository) GetPurchaseOrders(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, error) {
+	query := selectPurchaseOrder
+	var conditions []string
+	var args []any
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("po.status = $%d", len(args)))
+	}
+	if filter.SupplierID != 0 {
+		args = append(args, filter.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("po.supplier_id = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query(query+` ORDER BY po.created_at DESC, po.id DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purchase orders: %v", err)
+	}
+	defer rows.Close()
+
+	orders := []models.PurchaseOrder{}
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan purchase order: %v", err)
+		}
+		orders = append(orders, po)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+
+	lines, err := r.purchaseOrderLines(0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Lines = lines[orders[i].ID]
+	}
+	return orders, nil
+}
+
+func (r *supplierRepository) GetPurchaseOrder(id int) (models.PurchaseOrder, error) {
+	po, err := scanPurchaseOrder(r.db.QueryRow(selectPurchaseOrder+` WHERE po.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.PurchaseOrder{}, fmt.Errorf("purchase order with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return models.PurchaseOrder{}, fmt.Errorf("failed to query purchase order: %v", err)
+	}
+
+	lines, err := r.purchaseOrderLines(id)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	po.Lines = lines[id]
+	return po, nil
+}
+
+func (r *supplierRepository) CreatePurchaseOrder(po models.PurchaseOrder) (*models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := createPurchaseOrder(tx, &po); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return &po, nil
+}
+
+// CreatePurchaseOrders создаёт несколько черновиков одной транзакцией: либо все,
+// либо ни одного. Возвращает их ID в том же порядке.
+func (r *supplierRepository) CreatePurchaseOrders(orders []models.PurchaseOrder) ([]int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int, 0, len(orders))
+	for i := range orders {
+		if err := createPurchaseOrder(tx, &orders[i]); err != nil {
+			return nil, err
+		}
+		ids = append(ids, orders[i].ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return ids, nil
+}
+
+func createPurchaseOrder(tx *sql.Tx, po *models.PurchaseOrder) error {
+	err := tx.QueryRow(`
+        INSERT INTO purchase_orders (supplier_id, status, note, location_id)
+        VALUES ($1, 'draft', $2, $3) RETURNING id`,
+		po.SupplierID, po.Note, po.LocationID).Scan(&po.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create purchase order for supplier %d: %v", po.SupplierID, err)
+	}
+	return insertLines(tx, po.ID, po.Lines)
+}
+
+func insertLines(tx *sql.Tx, orderID int, lines []models.PurchaseOrderLine) error {
+	for i := range lines {
+		err := tx.QueryRow(`
+            INSERT INTO purchase_order_lines (purchase_order_id, ingredient_id, quantity, unit_price)
+            VALUES ($1, $2, $3, $4) RETURNING id`,
+			orderID, lines[i].IngredientID, lines[i].Quantity, lines[i].UnitPrice).Scan(&lines[i].ID)
+		if err != nil {
+			return fmt.Errorf("failed to save purchase order line for ingredient %d: %v", lines[i].IngredientID, err)
+		}
+	}
+	return nil
+}
+
+// lockStatus блокирует заказ до конца транзакции и возвращает его статус.
+func lockStatus(tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRow(`SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("purchase order with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return "", fmt.Errorf("failed to check purchase order: %v", err)
+	}
+	return status, nil
+}
+
+// UpdatePurchaseOrder меняет поставщика, примечание, локацию приёмки и целиком заменяет строки.
+// Менять можно только черновик.
+func (r *supplierRepository) UpdatePurchaseOrder(id int, po models.PurchaseOrder) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockStatus(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.PurchaseOrderDraft {
+		return fmt.Errorf("purchase order %d is %s, only drafts can be changed", id, status)
+	}
+
+	_, err = tx.Exec(`UPDATE purchase_orders SET supplier_id = $1, note = $2, location_id = $3 WHERE id = $4`, po.SupplierID, po.Note, po.LocationID, id)
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order: %v", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM purchase_order_lines WHERE purchase_order_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to clear purchase order lines: %v", err)
+	}
+	if err := insertLines(tx, id, po.Lines); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+func (r *supplierRepository) DeletePurchaseOrder(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockStatus(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.PurchaseOrderDraft {
+		return fmt.Errorf("purchase order %d is %s, only drafts can be deleted", id, status)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM purchase_orders WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete purchase order: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+func (r *supplierRepository) SendPurchaseOrder(id int, expectedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockStatus(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.PurchaseOrderDraft {
+		return fmt.Errorf("purchase order %d is already %s", id, status)
+	}
+
+	_, err = tx.Exec(`UPDATE purchase_orders SET status = 'sent', expected_at = $1 WHERE id = $2`, expectedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to send purchase order: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// ReceivePurchaseOrder приходует строки поставки (LineID уже определён): увеличивает
+// остатки, заводит партии со сроком годности, пишет в журнал записи 'purchase'
+// со ссылкой на заказ и переводит заказ в partially_received или received.
+// Принять больше заказанного нельзя.
+func (r *supplierRepository) ReceivePurchaseOrder(id int, lines []models.ReceiptLine, note string) ([]models.InventoryTransaction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockStatus(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status != models.PurchaseOrderSent && status != models.PurchaseOrderPartiallyReceived {
+		return nil, fmt.Errorf("purchase order %d is %s, only sent orders can be received", id, status)
+	}
+
+	var locationID int
+	if err := tx.QueryRow(`SELECT location_id FROM purchase_orders WHERE id = $1`, id).Scan(&locationID); err != nil {
+		return nil, fmt.Errorf("failed to check purchase order: %v", err)
+	}
+
+	if note == "" {
+		note = fmt.Sprintf("purchase order %d", id)
+	}
+
+	// Строки блокируются в порядке id, чтобы параллельные приёмки не взаимоблокировались
+	sorted := append([]models.ReceiptLine(nil), lines...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].LineID < sorted[j].LineID })
+
+	var transactions []models.InventoryTransaction
+	for _, line := range sorted {
+		var ingredientID int
+		var ordered, already, unitPrice float64
+		err := tx.QueryRow(`
+            SELECT ingredient_id, quantity, received_quantity, unit_price
+            FROM purchase_order_lines
+            WHERE id = $1 AND purchase_order_id = $2
+            FOR UPDATE`, line.LineID, id).Scan(&ingredientID, &ordered, &already, &unitPrice)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("line %d not found in purchase order %d: %w", line.LineID, id, cerrors.ErrNotExist)
+			}
+			return nil, fmt.Errorf("failed to check purchase order line: %v", err)
+		}
+		if already+line.Quantity > ordered {
+			return nil, fmt.Errorf("line %d: receiving %v would exceed the ordered %v (already received %v)", line.LineID, line.Quantity, ordered, already)
+		}
+
+		if _, err := tx.Exec(`UPDATE purchase_order_lines SET received_quantity = received_quantity + $1 WHERE id = $2`, line.Quantity, line.LineID); err != nil {
+			return nil, fmt.Errorf("failed to update purchase order line: %v", err)
+		}
+
+		t := models.InventoryTransaction{
+			IngredientID:    ingredientID,
+			ChangeAmount:    line.Quantity,
+			Type:            models.TransactionPurchase,
+			Note:            note,
+			PurchaseOrderID: &id,
+			ExpiresAt:       line.ExpiresAt,
+			UnitCost:        &unitPrice,
+			LocationID:      locationID,
+		}
+		err = tx.QueryRow(`UPDATE inventory SET stock = stock + $1 WHERE id = $2 RETURNING stock`, line.Quantity, ingredientID).Scan(&t.Balance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update inventory: %v", err)
+		}
+		err = tx.QueryRow(`
+            INSERT INTO inventory_transactions (ingredient_id, change_amount, transaction_type, note, purchase_order_id, unit_cost, location_id)
+            VALUES ($1, $2, 'purchase', $3, $4, $5, $6) RETURNING id, occurred_at`,
+			ingredientID, line.Quantity, note, id, unitPrice, locationID).Scan(&t.ID, &t.OccurredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to log inventory transaction: %v", err)
+		}
+		_, err = lot.Receive(tx, models.InventoryLot{
+			IngredientID:    ingredientID,
+			LocationID:      locationID,
+			Quantity:        line.Quantity,
+			ExpiresAt:       line.ExpiresAt,
+			PurchaseOrderID: &id,
+			Note:            note,
+		})
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+
+	var complete bool
+	err = tx.QueryRow(`
+        SELECT COALESCE(BOOL_AND(received_quantity >= quantity), TRUE)
+        FROM purchase_order_lines WHERE purchase_order_id = $1`, id).Scan(&complete)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check received quantities: %v", err)
+	}
+	status = models.PurchaseOrderPartiallyReceived
+	if complete {
+		status = models.PurchaseOrderReceived
+	}
+	if _, err := tx.Exec(`UPDATE purchase_orders SET status = $1 WHERE id = $2`, status, id); err != nil {
+		return nil, fmt.Errorf("failed to update purchase order status: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return transactions, nil
+}
+
+// GetOnOrder возвращает по ингредиентам количество, которое заказано, но ещё
+// не пришло; черновики тоже считаются, чтобы не заказать дважды.
+func (r *supplierRepository) GetOnOrder() (map[int]float64, error) {
+	rows, err := r.db.Query(`
+        SELECT l.ingredient_id, SUM(l.quantity - l.received_quantity)
+        FROM purchase_order_lines l
+        JOIN purchase_orders po ON po.id = l.purchase_order_id
+        WHERE po.status <> 'received'
+        GROUP BY l.ingredient_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quantities on order: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int]float64)
+	for rows.Next() {
+		var id int
+		var quantity float64
+		if err := rows.Scan(&id, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan quantity on order: %v", err)
+		}
+		result[id] = quantity
+	}
+	return result, rows.Err()
+}
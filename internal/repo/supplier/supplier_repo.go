@@ -0,0 +1,243 @@
+package supplier
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+
+	"github.com/lib/pq"
+)
+
+type SupplierRepository interface {
+	GetAll() ([]models.Supplier, error)
+	GetByID(id int) (models.Supplier, error)
+	Create(supplier models.Supplier) (*models.Supplier, error)
+	Update(id int, supplier models.Supplier) error
+	Delete(id int) error
+	GetPurchaseOrders(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, error)
+	GetPurchaseOrder(id int) (models.PurchaseOrder, error)
+	CreatePurchaseOrder(po models.PurchaseOrder) (*models.PurchaseOrder, error)
+	CreatePurchaseOrders(orders []models.PurchaseOrder) ([]int, error)
+	UpdatePurchaseOrder(id int, po models.PurchaseOrder) error
+	DeletePurchaseOrder(id int) error
+	SendPurchaseOrder(id int, expectedAt time.Time) error
+	ReceivePurchaseOrder(id int, lines []models.ReceiptLine, note string) ([]models.InventoryTransaction, error)
+	GetOnOrder() (map[int]float64, error)
+}
+
+type supplierRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) SupplierRepository {
+	return &supplierRepository{
+		db: db,
+	}
+}
+
+func pqCode(err error) pq.ErrorCode {
+	if pqErr, ok := err.(*pq.Error); ok {
+		return pqErr.Code
+	}
+	return ""
+}
+
+const selectSupplierItems = `
+        SELECT si.supplier_id, si.ingredient_id, i.name, i.unit, si.unit_price
+        FROM supplier_items si
+        JOIN inventory i ON i.id = si.ingredient_id`
+
+// supplierItems возвращает прайсы поставщиков; supplierID = 0 — всех.
+func (r *supplierRepository) supplierItems(supplierID int) (map[int][]models.SupplierItem, error) {
+	query := selectSupplierItems
+	var args []any
+	if supplierID != 0 {
+		query += ` WHERE si.supplier_id = $1`
+		args = append(args, supplierID)
+	}
+	rows, err := r.db.Query(query+` ORDER BY i.name`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier items: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int][]models.SupplierItem)
+	for rows.Next() {
+		var id int
+		var item models.SupplierItem
+		if err := rows.Scan(&id, &item.IngredientID, &item.Name, &item.Unit, &item.UnitPrice); err != nil {
+			return nil, fmt.Errorf("failed to scan supplier item: %v", err)
+		}
+		result[id] = append(result[id], item)
+	}
+	return result, rows.Err()
+}
+
+const selectSupplier = `
+        SELECT id, name, contact_name, email, phone, lead_time_days
+        FROM suppliers`
+
+func scanSupplier(row interface{ Scan(...any) error }) (models.Supplier, error) {
+	var s models.Supplier
+	err := row.Scan(&s.ID, &s.Name, &s.ContactName, &s.Email, &s.Phone, &s.LeadTimeDays)
+	return s, err
+}
+
+func (r *supplierRepository) GetAll() ([]models.Supplier, error) {
+	rows, err := r.db.Query(selectSupplier + ` ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query suppliers: %v", err)
+	}
+	defer rows.Close()
+
+	suppliers := []models.Supplier{}
+	for rows.Next() {
+		s, err := scanSupplier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier: %v", err)
+		}
+		suppliers = append(suppliers, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+
+	items, err := r.supplierItems(0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range suppliers {
+		suppliers[i].Items = items[suppliers[i].ID]
+		if suppliers[i].Items == nil {
+			suppliers[i].Items = []models.SupplierItem{}
+		}
+	}
+	return suppliers, nil
+}
+
+func (r *supplierRepository) GetByID(id int) (models.Supplier, error) {
+	s, err := scanSupplier(r.db.QueryRow(selectSupplier+` WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Supplier{}, fmt.Errorf("supplier with ID %d not found: %w", id, cerrors.ErrNotExist)
+		}
+		return models.Supplier{}, fmt.Errorf("failed to query supplier: %v", err)
+	}
+
+	items, err := r.supplierItems(id)
+	if err != nil {
+		return models.Supplier{}, err
+	}
+	s.Items = items[id]
+	if s.Items == nil {
+		s.Items = []models.SupplierItem{}
+	}
+	return s, nil
+}
+
+func (r *supplierRepository) Create(supplier models.Supplier) (*models.Supplier, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+        INSERT INTO suppliers (name, contact_name, email, phone, lead_time_days)
+        VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		supplier.Name, supplier.ContactName, supplier.Email, supplier.Phone, supplier.LeadTimeDays).
+		Scan(&supplier.ID)
+	if err != nil {
+		if pqCode(err) == "23505" {
+			return nil, cerrors.ErrExist
+		}
+		return nil, fmt.Errorf("failed to create supplier: %v", err)
+	}
+
+	if err := setSupplierItems(tx, supplier.ID, supplier.Items); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return &supplier, nil
+}
+
+// Update меняет карточку поставщика и целиком заменяет его прайс.
+func (r *supplierRepository) Update(id int, supplier models.Supplier) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        UPDATE suppliers
+        SET name = $1, contact_name = $2, email = $3, phone = $4, lead_time_days = $5
+        WHERE id = $6`,
+		supplier.Name, supplier.ContactName, supplier.Email, supplier.Phone, supplier.LeadTimeDays, id)
+	if err != nil {
+		if pqCode(err) == "23505" {
+			return cerrors.ErrExist
+		}
+		return fmt.Errorf("failed to update supplier: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("supplier with ID %d not found: %w", id, cerrors.ErrNotExist)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM supplier_items WHERE supplier_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to clear supplier items: %v", err)
+	}
+	if err := setSupplierItems(tx, id, supplier.Items); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+func setSupplierItems(tx *sql.Tx, supplierID int, items []models.SupplierItem) error {
+	for _, item := range items {
+		_, err := tx.Exec(`
+            INSERT INTO supplier_items (supplier_id, ingredient_id, unit_price)
+            VALUES ($1, $2, $3)`,
+			supplierID, item.IngredientID, item.UnitPrice)
+		if err != nil {
+			return fmt.Errorf("failed to save supplier item %d: %v", item.IngredientID, err)
+		}
+	}
+	return nil
+}
+
+// Delete удаляет поставщика вместе с прайсом. Поставщика с заказами удалить
+// нельзя: заказы остаются историей поставок.
+func (r *supplierRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM suppliers WHERE id = $1`, id)
+	if err != nil {
+		if pqCode(err) == "23503" {
+			return fmt.Errorf("supplier %d has purchase orders: %w", id, cerrors.ErrInUse)
+		}
+		return fmt.Errorf("failed to delete supplier: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("supplier with ID %d not found: %w", id, cerrors.ErrNotExist)
+	}
+	return nil
+}
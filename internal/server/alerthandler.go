@@ -0,0 +1,22 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetStockAlerts отдаёт ингредиенты на пороге дозаказа или ниже с оценкой,
+// на сколько дней хватит остатка.
+func (h *Handler) GetStockAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := h.Service.GetStockAlerts()
+	if err != nil {
+		http.Error(w, "Failed to retrieve stock alerts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(alerts); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+func (h *Handler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	windows, err := h.Service.GetAvailability(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get availability: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(windows); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) SetAvailability(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	var windows []models.AvailabilityWindow
+	if err := json.NewDecoder(r.Body).Decode(&windows); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.Service.SetAvailability(id, windows)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(saved); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetPriceRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.Service.GetPriceRules()
+	if err != nil {
+		http.Error(w, "Failed to get price rules: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rules); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetPriceRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid price rule ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.Service.GetPriceRule(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Price rule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get price rule: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rule); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) CreatePriceRule(w http.ResponseWriter, r *http.Request) {
+	rule := models.PriceRule{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.Service.CreatePriceRule(rule)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) UpdatePriceRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid price rule ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	var rule models.PriceRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.UpdatePriceRule(id, rule); err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Price rule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) DeletePriceRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid price rule ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.DeletePriceRule(id); err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Price rule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete price rule: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// GetIngredientForecast отдаёт прогноз расхода ингредиентов;
+// ?days= — горизонт, ?weeks= — сколько недель истории усреднять.
+func (h *Handler) GetIngredientForecast(w http.ResponseWriter, r *http.Request) {
+	var days, weeks int
+	var err error
+	if v := r.URL.Query().Get("days"); v != "" {
+		if days, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "Invalid days: must be an integer", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := r.URL.Query().Get("weeks"); v != "" {
+		if weeks, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "Invalid weeks: must be an integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.Service.GetIngredientForecast(days, weeks)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
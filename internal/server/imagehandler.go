@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"frappuccino/internal/models"
+	"frappuccino/internal/svc"
+	"frappuccino/pkg/cerrors"
+	"frappuccino/pkg/storage"
+)
+
+// UploadMenuItemImages принимает multipart/form-data, файлы в поле "image" (можно несколько).
+func (h *Handler) UploadMenuItemImages(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 5*svc.MaxImageSize)
+	if err := r.ParseMultipartForm(svc.MaxImageSize); err != nil {
+		http.Error(w, "Invalid multipart form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	files := r.MultipartForm.File["image"]
+	if len(files) == 0 {
+		http.Error(w, "Please attach at least one file in the \"image\" field", http.StatusBadRequest)
+		return
+	}
+
+	// Сначала читаем все файлы: сервис сохраняет их только если все прошли проверку
+	uploads := make([]models.ImageUpload, 0, len(files))
+	for _, fh := range files {
+		f, err := fh.Open()
+		if err != nil {
+			http.Error(w, "Failed to read "+fh.Filename+": "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(f, svc.MaxImageSize+1))
+		f.Close()
+		if err != nil {
+			http.Error(w, "Failed to read "+fh.Filename+": "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		uploads = append(uploads, models.ImageUpload{Name: fh.Filename, Data: data})
+	}
+
+	uploaded, err := h.Service.UploadMenuItemImages(id, uploads)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(uploaded); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetMenuItemImages(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	images, err := h.Service.GetMenuItemImages(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get images: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(images); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) DeleteMenuItemImage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+	imageID, err := strconv.Atoi(r.PathValue("imageId"))
+	if err != nil {
+		http.Error(w, "Invalid image ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.DeleteMenuItemImage(id, imageID); err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Image not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete image: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ServeMedia(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	f, err := h.Service.OpenMedia(name)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, "Failed to open file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	// Имена файлов случайные и не переиспользуются, поэтому кэшировать можно надолго
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(w, r, name, time.Time{}, f)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"frappuccino/internal/models"
 	"frappuccino/internal/svc"
@@ -23,6 +24,12 @@ func New(s svc.Svc) *Handler {
 }
 
 func (h *Handler) GetInventoryItems(w http.ResponseWriter, r *http.Request) {
+	// Исторические остатки отдаются в своём формате
+	if r.URL.Query().Has("asOf") {
+		h.GetInventorySnapshot(w, r)
+		return
+	}
+
 	statusCode := 200
 	text := "inventory got"
 
@@ -154,7 +161,14 @@ func (h *Handler) UpDate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.Service.InventoryUpDate(id, newItems); err != nil {
+	locationID, err := locationParam(r)
+	if err != nil {
+		statusCode = 400
+		text = err.Error()
+		return
+	}
+
+	if err := h.Service.InventoryUpDate(id, newItems, locationID); err != nil {
 		if errors.Is(err, cerrors.ErrNotExist) {
 			statusCode = 404
 			text = cerrors.ErrNotExist.Error()
@@ -196,9 +210,16 @@ func (h *Handler) DeleteItem(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) GetLeftOvers(w http.ResponseWriter, r *http.Request) {
 	// Извлекаем параметры из query
-	sortBy := r.URL.Query().Get("sortBy")
-	pageStr := r.URL.Query().Get("page")
-	pageSizeStr := r.URL.Query().Get("pageSize")
+	query := r.URL.Query()
+	filter := models.LeftOverFilter{
+		SortBy:     query.Get("sortBy"),
+		SortOrder:  strings.ToLower(query.Get("sortOrder")),
+		Unit:       query.Get("unit"),
+		NamePrefix: query.Get("name"),
+		Cursor:     query.Get("cursor"),
+	}
+	pageStr := query.Get("page")
+	pageSizeStr := query.Get("pageSize")
 
 	// Устанавливаем значения по умолчанию, если параметры не переданы
 	if pageStr == "" {
@@ -209,26 +230,48 @@ func (h *Handler) GetLeftOvers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Преобразуем page и pageSize в int
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
+	var err error
+	filter.Page, err = strconv.Atoi(pageStr)
+	if err != nil || filter.Page < 1 {
 		http.Error(w, "Invalid page parameter", http.StatusBadRequest)
 		return
 	}
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize < 1 {
+	filter.PageSize, err = strconv.Atoi(pageSizeStr)
+	if err != nil || filter.PageSize < 1 {
 		http.Error(w, "Invalid pageSize parameter", http.StatusBadRequest)
 		return
 	}
 
-	// Логика для сортировки
-	if sortBy != "price" && sortBy != "quantity" && sortBy != "" {
-		http.Error(w, "Invalid sortBy parameter", http.StatusBadRequest)
-		return
+	// Фильтры
+	if v := query.Get("belowThreshold"); v != "" {
+		if filter.BelowThreshold, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Invalid belowThreshold parameter", http.StatusBadRequest)
+			return
+		}
+	}
+	for _, p := range []struct {
+		name  string
+		value **float64
+	}{{"minStock", &filter.MinStock}, {"maxStock", &filter.MaxStock}} {
+		v := query.Get(p.name)
+		if v == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "Invalid "+p.name+" parameter", http.StatusBadRequest)
+			return
+		}
+		*p.value = &parsed
 	}
 
 	// Получаем остатки инвентаря с учетом параметров
-	results, err := h.Service.GetLeftOvers(sortBy, page, pageSize)
+	results, err := h.Service.GetLeftOvers(filter)
 	if err != nil {
+		if errors.Is(err, cerrors.ErrInvalidParam) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Failed to get leftovers: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
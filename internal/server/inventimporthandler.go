@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"frappuccino/helper"
+	"frappuccino/pkg/cerrors"
+)
+
+// ExportInventory отдаёт склад в ?format=json (по умолчанию) или ?format=csv.
+func (h *Handler) ExportInventory(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		http.Error(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.Service.ExportInventory()
+	if err != nil {
+		http.Error(w, "Failed to export inventory: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="inventory.`+format+`"`)
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		if err := helper.WriteInventoryCSV(w, items); err != nil {
+			http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(items); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ImportInventory принимает файл склада. Формат берётся из ?format=, иначе из
+// Content-Type; ?mode=add прибавляет stock как поставку в ?locationId=,
+// ?dryRun=true только проверяет строки. Если есть отклонённые строки, склад
+// не меняется и отчёт приходит с 422.
+func (h *Handler) ImportInventory(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "json"
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "text/csv" {
+			format = "csv"
+		}
+	}
+	mode := strings.ToLower(r.URL.Query().Get("mode"))
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+	locationID, err := locationParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Service.ImportInventory(http.MaxBytesReader(w, r.Body, maxImportSize), format, mode, locationID, dryRun)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !dryRun && len(result.Rejected) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
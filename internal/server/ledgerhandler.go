@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+// GetInventoryTransactions отдаёт журнал движения ингредиента;
+// фильтры ?startDate=, ?endDate=, ?type= и ?locationId=.
+func (h *Handler) GetInventoryTransactions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inventory ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	locationID, err := locationParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.TransactionFilter{
+		StartDate:  query.Get("startDate"),
+		EndDate:    query.Get("endDate"),
+		Type:       query.Get("type"),
+		LocationID: locationID,
+	}
+
+	transactions, err := h.Service.GetInventoryTransactions(id, filter)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(transactions); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) RecordInventoryTransaction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inventory ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	var t models.InventoryTransaction
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recorded, err := h.Service.RecordInventoryTransaction(id, t)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(recorded); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) CheckInventoryLedger(w http.ResponseWriter, r *http.Request) {
+	check, err := h.Service.CheckInventoryLedger()
+	if err != nil {
+		http.Error(w, "Failed to check inventory ledger: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(check); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
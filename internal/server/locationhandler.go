@@ -0,0 +1,112 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+// locationParam читает необязательный ?locationId=; 0 — не задан.
+func locationParam(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("locationId")
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.New("Invalid locationId: must be an integer")
+	}
+	return id, nil
+}
+
+func (h *Handler) GetLocations(w http.ResponseWriter, r *http.Request) {
+	locations, err := h.Service.GetLocations()
+	if err != nil {
+		http.Error(w, "Failed to retrieve locations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(locations); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid location ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	location, err := h.Service.GetLocation(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Location not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to retrieve location", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(location); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) CreateLocation(w http.ResponseWriter, r *http.Request) {
+	var location models.Location
+	if err := json.NewDecoder(r.Body).Decode(&location); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.Service.CreateLocation(location)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrExist) {
+			http.Error(w, "Location already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetLocationStock отдаёт остатки локации по её партиям и товар в пути к ней.
+func (h *Handler) GetLocationStock(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid location ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	stock, err := h.Service.GetLocationStock(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Location not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to retrieve location stock", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stock); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/pkg/cerrors"
+)
+
+// GetInventoryLots отдаёт партии ингредиента в порядке расхода;
+// ?all=true — вместе с израсходованными, ?locationId= — только в локации.
+func (h *Handler) GetInventoryLots(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inventory ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+	all := r.URL.Query().Get("all") == "true"
+	locationID, err := locationParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lots, err := h.Service.GetInventoryLots(id, locationID, all)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get lots: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(lots); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetExpiringLots отдаёт партии, срок годности которых истекает в ближайшие
+// ?days= дней (по умолчанию 3), и уже просроченные; ?locationId= — только в локации.
+func (h *Handler) GetExpiringLots(w http.ResponseWriter, r *http.Request) {
+	days := 3
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid days: must be an integer", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+	locationID, err := locationParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lots, err := h.Service.GetExpiringLots(days, locationID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(lots); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
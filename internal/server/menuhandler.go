@@ -3,6 +3,7 @@ package server
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
@@ -38,6 +39,17 @@ func (h *Handler) CreateNewMenuItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	publish, err := publishParam(r)
+	if err != nil {
+		statusCode = 400
+		text = err.Error()
+		return
+	}
+	if !publish {
+		statusCode, text = h.stageMenuChange(models.MenuDraftChange{Action: models.DraftActionCreate, Item: &newItem})
+		return
+	}
+
 	_, err = h.Service.CreateMenuItem(newItem)
 	if err != nil {
 		if errors.Is(err, cerrors.ErrExist) {
@@ -59,7 +71,15 @@ func (h *Handler) GetAllMenuItems(w http.ResponseWriter, r *http.Request) {
 		Respond(w, statusCode, text)
 	}()
 
-	data, err := h.Service.GetAllMenuItems()
+	filter, err := menuFilter(r)
+	if err != nil {
+		statusCode = 400
+		text = err.Error()
+		return
+	}
+	w.Header().Set("Vary", "Accept-Language")
+
+	data, err := h.Service.ListMenu(filter)
 	if err != nil {
 		statusCode = 400
 		text = err.Error()
@@ -145,12 +165,26 @@ func (h *Handler) UpdateMenuItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	publish, err := publishParam(r)
+	if err != nil {
+		statusCode = 400
+		text = err.Error()
+		return
+	}
+	if !publish {
+		statusCode, text = h.stageMenuChange(models.MenuDraftChange{Action: models.DraftActionUpdate, MenuItemID: &id, Item: &updatedItem})
+		return
+	}
+
 	_, err = h.Service.UpdateMenuItem(id, updatedItem)
 	if err != nil {
 		switch {
 		case errors.Is(err, cerrors.ErrMenuItemNotFound):
 			statusCode = 404
 			text = cerrors.ErrMenuItemNotFound.Error()
+		case errors.Is(err, cerrors.ErrExist):
+			statusCode = 409
+			text = err.Error()
 		default:
 			statusCode = 400
 			text = err.Error()
@@ -175,6 +209,17 @@ func (h *Handler) DeleteMenuItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	publish, err := publishParam(r)
+	if err != nil {
+		statusCode = 400
+		text = err.Error()
+		return
+	}
+	if !publish {
+		statusCode, text = h.stageMenuChange(models.MenuDraftChange{Action: models.DraftActionDelete, MenuItemID: &id})
+		return
+	}
+
 	if err := h.Service.DeleteMenuItem(id); err != nil {
 		text = err.Error()
 
@@ -182,11 +227,44 @@ func (h *Handler) DeleteMenuItem(w http.ResponseWriter, r *http.Request) {
 			statusCode = 404
 			return
 		}
+		if errors.Is(err, cerrors.ErrInUse) {
+			statusCode = 409
+			return
+		}
 		statusCode = 400
 		return
 	}
 }
 
+// publishParam читает ?publish=true: без него POST, PUT и DELETE /menu только
+// добавляют изменение в черновик, а в живое меню оно попадает при публикации.
+func publishParam(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("publish")
+	if v == "" {
+		return false, nil
+	}
+	publish, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.New("Invalid publish: must be true or false")
+	}
+	return publish, nil
+}
+
+// stageMenuChange добавляет изменение в черновик и возвращает код и текст ответа.
+func (h *Handler) stageMenuChange(change models.MenuDraftChange) (int, string) {
+	staged, err := h.Service.StageMenuChange(change)
+	if err != nil {
+		switch {
+		case errors.Is(err, cerrors.ErrMenuItemNotFound):
+			return 404, cerrors.ErrMenuItemNotFound.Error()
+		case errors.Is(err, cerrors.ErrInUse):
+			return 409, err.Error()
+		}
+		return 400, err.Error()
+	}
+	return 202, fmt.Sprintf("change staged as draft change %d, publish it with POST /menu-draft/publish", staged.ID)
+}
+
 func Respond(w http.ResponseWriter, statusCode int, text string) {
 	w.WriteHeader(statusCode)
 	str := converter.Wrap(statusCode, text)
@@ -199,3 +277,44 @@ func Respond(w http.ResponseWriter, statusCode int, text string) {
 	w.WriteHeader(statusCode)
 	w.Write(bb)
 }
+
+// menuFilter разбирает параметры запроса меню:
+// ?all=true отдаёт всё меню, включая позиции, недоступные в текущий момент;
+// ?category= принимает id или имя категории и включает подкатегории;
+// ?locationId= считает portions_available и sold_out по складу этого кафе;
+// ?maxCalories=, ?maxSugar=, ?maxCaffeine= ограничивают пищевую ценность порции;
+// язык выбирается по ?lang= или Accept-Language
+func menuFilter(r *http.Request) (models.MenuFilter, error) {
+	query := r.URL.Query()
+	all, _ := strconv.ParseBool(query.Get("all"))
+	filter := models.MenuFilter{All: all, Category: query.Get("category")}
+	locationID, err := locationParam(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.LocationID = locationID
+
+	limits := map[string]**float64{
+		"maxCalories": &filter.MaxCalories,
+		"maxSugar":    &filter.MaxSugar,
+		"maxCaffeine": &filter.MaxCaffeine,
+	}
+	for name, target := range limits {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 {
+			return filter, fmt.Errorf("invalid %s: %q", name, raw)
+		}
+		*target = &value
+	}
+
+	languages, err := requestLanguages(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.Languages = languages
+	return filter, nil
+}
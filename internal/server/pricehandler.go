@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+func (h *Handler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.Service.GetPriceHistory(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get price history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetScheduledPriceChanges(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.Service.GetScheduledPriceChanges(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get scheduled price changes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(changes); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) SchedulePriceChange(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	var change models.ScheduledPriceChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.Service.SchedulePriceChange(id, change)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) CancelScheduledPriceChange(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+	changeID, err := strconv.Atoi(r.PathValue("changeId"))
+	if err != nil {
+		http.Error(w, "Invalid price change ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.CancelScheduledPriceChange(id, changeID); err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Pending price change not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to cancel price change: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+// GetReorderSuggestions отдаёт предложения дозаказа;
+// параметры ?horizonDays=, ?usageDays= и ?supplierId=.
+func (h *Handler) GetReorderSuggestions(w http.ResponseWriter, r *http.Request) {
+	var filter models.ReorderFilter
+	params := []struct {
+		name  string
+		value *int
+	}{
+		{"horizonDays", &filter.HorizonDays},
+		{"usageDays", &filter.UsageDays},
+		{"supplierId", &filter.SupplierID},
+	}
+	for _, p := range params {
+		v := r.URL.Query().Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid "+p.name+": must be an integer", http.StatusBadRequest)
+			return
+		}
+		*p.value = n
+	}
+
+	suggestions, err := h.Service.GetReorderSuggestions(filter)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(suggestions); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreateReorderDrafts создаёт черновики заказов поставщикам из предложений
+// дозаказа; пустое тело — все предложения с параметрами по умолчанию.
+func (h *Handler) CreateReorderDrafts(w http.ResponseWriter, r *http.Request) {
+	var req models.ReorderDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	orders, err := h.Service.CreateReorderDrafts(req)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(orders); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+func (h *Handler) GetSubstitutes(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inventory ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	subs, err := h.Service.GetSubstitutes(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get substitutes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(subs); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetSubstitute добавляет замену ингредиента; повторный запрос с тем же
+// substitute_id меняет ratio и priority.
+func (h *Handler) SetSubstitute(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inventory ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	var sub models.IngredientSubstitute
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.Service.SetSubstitute(id, sub)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(saved); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) DeleteSubstitute(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inventory ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+	substituteID, err := strconv.Atoi(r.PathValue("substituteId"))
+	if err != nil {
+		http.Error(w, "Invalid substitute ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.DeleteSubstitute(id, substituteID); err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Substitute not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete substitute: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetSubstitutionReport(w http.ResponseWriter, r *http.Request) {
+	startDate := r.URL.Query().Get("startDate")
+	endDate := r.URL.Query().Get("endDate")
+
+	records, err := h.Service.GetSubstitutionReport(startDate, endDate)
+	if err != nil {
+		http.Error(w, "Failed to get substitutions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
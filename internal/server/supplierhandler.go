@@ -0,0 +1,125 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+func (h *Handler) GetSuppliers(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := h.Service.GetSuppliers()
+	if err != nil {
+		http.Error(w, "Failed to get suppliers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(suppliers); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetSupplier(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid supplier ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	supplier, err := h.Service.GetSupplier(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get supplier: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(supplier); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.Service.CreateSupplier(supplier)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrExist) {
+			http.Error(w, "Supplier already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateSupplier меняет карточку поставщика; items заменяют прайс целиком.
+func (h *Handler) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid supplier ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.UpdateSupplier(id, supplier); err != nil {
+		switch {
+		case errors.Is(err, cerrors.ErrNotExist):
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+		case errors.Is(err, cerrors.ErrExist):
+			http.Error(w, "Supplier already exists", http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid supplier ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.DeleteSupplier(id); err != nil {
+		switch {
+		case errors.Is(err, cerrors.ErrNotExist):
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+		case errors.Is(err, cerrors.ErrInUse):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Failed to delete supplier: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+// GetTransfers отдаёт перемещения, новые сверху; фильтры ?status= и ?locationId=
+// (откуда или куда).
+func (h *Handler) GetTransfers(w http.ResponseWriter, r *http.Request) {
+	locationID, err := locationParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter := models.TransferFilter{
+		Status:     r.URL.Query().Get("status"),
+		LocationID: locationID,
+	}
+
+	transfers, err := h.Service.GetTransfers(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(transfers); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid transfer ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.Service.GetTransfer(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Transfer not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to retrieve transfer", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(transfer); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreateTransfer отправляет товар между локациями; он списывается у источника сразу.
+func (h *Handler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	var transfer models.StockTransfer
+	if err := json.NewDecoder(r.Body).Decode(&transfer); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.Service.CreateTransfer(transfer)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) ReceiveTransfer(w http.ResponseWriter, r *http.Request) {
+	h.closeTransfer(w, r, h.Service.ReceiveTransfer)
+}
+
+func (h *Handler) CancelTransfer(w http.ResponseWriter, r *http.Request) {
+	h.closeTransfer(w, r, h.Service.CancelTransfer)
+}
+
+func (h *Handler) closeTransfer(w http.ResponseWriter, r *http.Request, close func(int) (*models.StockTransfer, error)) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid transfer ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := close(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Transfer not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(transfer); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+// requestLanguages — ?lang= важнее заголовка Accept-Language.
+func requestLanguages(r *http.Request) ([]string, error) {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		locale, err := helper.NormalizeLocale(lang)
+		if err != nil {
+			return nil, err
+		}
+		return []string{locale}, nil
+	}
+	return helper.ParseAcceptLanguage(r.Header.Get("Accept-Language")), nil
+}
+
+func (h *Handler) GetTranslations(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	translations, err := h.Service.GetTranslations(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get translations: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(translations); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	var t models.MenuItemTranslation
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.Service.SetTranslation(id, r.PathValue("locale"), t)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(saved); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu item ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.DeleteTranslation(id, r.PathValue("locale")); err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Translation not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,18 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/pkg/units"
+)
+
+// GetUnits отдаёт поддерживаемые единицы измерения с коэффициентами перевода
+// в базовую единицу своего измерения (g, ml, pcs).
+func (h *Handler) GetUnits(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(units.All()); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/models"
+)
+
+// GetInventoryValuation отдаёт стоимость остатков и себестоимость продаж;
+// ?method=fifo|wac, ?startDate=, ?endDate= и ?period=day|week|month.
+func (h *Handler) GetInventoryValuation(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ValuationFilter{
+		Method:    query.Get("method"),
+		StartDate: query.Get("startDate"),
+		EndDate:   query.Get("endDate"),
+		Period:    query.Get("period"),
+	}
+
+	report, err := h.Service.GetInventoryValuation(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
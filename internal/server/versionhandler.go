@@ -0,0 +1,208 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+func (h *Handler) GetMenuDraft(w http.ResponseWriter, r *http.Request) {
+	draft, err := h.Service.GetMenuDraft()
+	if err != nil {
+		http.Error(w, "Failed to get menu draft: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(draft); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) DiscardMenuDraft(w http.ResponseWriter, r *http.Request) {
+	if err := h.Service.DiscardMenuDraft(); err != nil {
+		http.Error(w, "Failed to discard menu draft: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) StageMenuChange(w http.ResponseWriter, r *http.Request) {
+	var change models.MenuDraftChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	staged, err := h.Service.StageMenuChange(change)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrMenuItemNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, cerrors.ErrInUse) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(staged); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) DeleteMenuChange(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid draft change ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.DeleteMenuChange(id); err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Draft change not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete draft change: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) PublishMenuDraft(w http.ResponseWriter, r *http.Request) {
+	// Тело необязательно: {"note": "..."}
+	var request struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.Service.PublishMenuDraft(request.Note)
+	if err != nil {
+		// Изменения проверены при подготовке: 4xx — только если меню с тех пор разошлось
+		// с черновиком, остальное — ошибки сервера или базы
+		switch {
+		case errors.Is(err, cerrors.ErrInUse), errors.Is(err, cerrors.ErrExist):
+			http.Error(w, "Failed to publish menu draft: "+err.Error(), http.StatusConflict)
+		case errors.Is(err, cerrors.ErrMenuItemNotFound), errors.Is(err, cerrors.ErrNotExist):
+			http.Error(w, "Failed to publish menu draft: "+err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to publish menu draft: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(version); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetMenuVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := h.Service.GetMenuVersions()
+	if err != nil {
+		http.Error(w, "Failed to get menu versions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(versions); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) GetMenuVersion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu version ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.Service.GetMenuVersion(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Menu version not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get menu version: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(version); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) DiffMenuVersions(w http.ResponseWriter, r *http.Request) {
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid 'from' version: must be an integer", http.StatusBadRequest)
+		return
+	}
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid 'to' version: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.Service.DiffMenuVersions(from, to)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Menu version not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to diff menu versions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(diff); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) RollbackMenu(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid menu version ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.Service.RollbackMenu(id)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, "Menu version not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to roll back menu: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(version); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
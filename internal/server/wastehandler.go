@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+// RecordWaste списывает товар с причиной expired, spilled или quality.
+func (h *Handler) RecordWaste(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid inventory ID: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	var entry models.WasteEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recorded, err := h.Service.RecordWaste(id, entry)
+	if err != nil {
+		if errors.Is(err, cerrors.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(recorded); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetWasteReport отдаёт списания по ингредиентам;
+// фильтры ?startDate=, ?endDate= и разбивка ?period=day|week|month.
+func (h *Handler) GetWasteReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.WasteFilter{
+		StartDate: query.Get("startDate"),
+		EndDate:   query.Get("endDate"),
+		Period:    query.Get("period"),
+	}
+
+	report, err := h.Service.GetWasteReport(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
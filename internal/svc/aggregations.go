@@ -2,6 +2,7 @@ package svc
 
 import (
 	"fmt"
+	"math"
 
 	"frappuccino/internal/models"
 )
@@ -13,28 +14,18 @@ func (s *svc) GetTotalSales() (float64, error) {
 		return 0, err
 	}
 
-	menuAll, err := s.Repo.MenuRepo.GetAllMenuItems()
-	if err != nil {
-		s.Log.Error("Failed to retrieve menu items", "error", err.Error())
-		return 0, err
-	}
-
-	menuMap := make(map[int]models.MenuItem)
-	for _, item := range menuAll {
-		menuMap[item.ID] = item
-	}
-
+	// Считаем по ценам, по которым позиции проданы (со скидками happy hour),
+	// а не по текущему прайсу меню
 	total := 0.0
 	for _, order := range orders {
 		if order.Status != "closed" {
 			continue
 		}
 		for _, item := range order.Items {
-			if menuItem, exists := menuMap[item.MenuItemID]; exists {
-				total += menuItem.Price * float64(item.Quantity)
-			}
+			total += item.Price * float64(item.Quantity)
 		}
 	}
+	total = math.Round(total*100) / 100
 
 	if total == 0 {
 		s.Log.Info("No closed orders found, total sales: 0", "total", total)
@@ -0,0 +1,133 @@
+package svc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/notify"
+)
+
+// usageWindowDays — за сколько дней усредняется расход для оценки запаса.
+const usageWindowDays = 14
+
+// GetStockAlerts возвращает ингредиенты на пороге дозаказа или ниже; первыми
+// идут те, что закончатся раньше.
+func (s *svc) GetStockAlerts() ([]models.StockAlert, error) {
+	inventory, err := s.availableInventory()
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.Repo.InventoryRepo.GetDailyUsage(usageWindowDays)
+	if err != nil {
+		s.Log.Error("Failed to retrieve daily usage", "error", err.Error())
+		return nil, err
+	}
+
+	alerts := []models.StockAlert{}
+	for _, item := range inventory {
+		if item.Stock > item.ReorderThreshold {
+			continue
+		}
+		alerts = append(alerts, stockAlert(item, usage[item.ID]))
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		a, b := alerts[i].DaysOfCover, alerts[j].DaysOfCover
+		switch {
+		case a != nil && b != nil:
+			return *a < *b
+		case a != nil || b != nil:
+			// Без расхода оценки нет — такие в конце
+			return a != nil
+		}
+		return alerts[i].Stock < alerts[j].Stock
+	})
+	return alerts, nil
+}
+
+func stockAlert(item models.InventoryItem, dailyUsage float64) models.StockAlert {
+	alert := models.StockAlert{
+		IngredientID:     item.ID,
+		Name:             item.Name,
+		Stock:            item.Stock,
+		Unit:             item.Unit,
+		ReorderThreshold: item.ReorderThreshold,
+		DailyUsage:       math.Round(dailyUsage*10000) / 10000,
+		Level:            models.AlertLevelLow,
+	}
+	if item.Stock <= 0 {
+		alert.Level = models.AlertLevelOut
+	}
+	if dailyUsage > 0 {
+		days := math.Round(item.Stock/dailyUsage*10) / 10
+		alert.DaysOfCover = &days
+	}
+	return alert
+}
+
+// notifyLowStock шлёт уведомление по ингредиентам, остаток которых после
+// изменения changes перешёл через порог дозаказа или закончился. Повторно,
+// пока остаток остаётся ниже порога, уведомление не отправляется.
+// Ошибки только логируются: складская операция к этому моменту уже проведена.
+func (s *svc) notifyLowStock(changes map[int]float64) {
+	if s.Notifier == nil || len(changes) == 0 {
+		return
+	}
+
+	inventory, err := s.availableInventory()
+	if err != nil {
+		return
+	}
+
+	var crossed []models.InventoryItem
+	for _, item := range inventory {
+		change, ok := changes[item.ID]
+		if !ok || change >= 0 {
+			continue
+		}
+		before := item.Stock - change
+		lowNow := item.Stock <= item.ReorderThreshold && before > item.ReorderThreshold
+		outNow := item.Stock <= 0 && before > 0
+		if lowNow || outNow {
+			crossed = append(crossed, item)
+		}
+	}
+	if len(crossed) == 0 {
+		return
+	}
+
+	usage, err := s.Repo.InventoryRepo.GetDailyUsage(usageWindowDays)
+	if err != nil {
+		s.Log.Error("Failed to retrieve daily usage", "error", err.Error())
+		usage = map[int]float64{}
+	}
+
+	alerts := make([]models.StockAlert, 0, len(crossed))
+	lines := make([]string, 0, len(crossed))
+	for _, item := range crossed {
+		alert := stockAlert(item, usage[item.ID])
+		alerts = append(alerts, alert)
+
+		line := fmt.Sprintf("%s: %v %s left (reorder at %v)", alert.Name, alert.Stock, alert.Unit, alert.ReorderThreshold)
+		if alert.DaysOfCover != nil {
+			line += fmt.Sprintf(", about %v days of cover", *alert.DaysOfCover)
+		}
+		lines = append(lines, line)
+	}
+
+	msg := notify.Message{
+		Subject: fmt.Sprintf("Low stock: %d ingredient(s)", len(alerts)),
+		Text:    strings.Join(lines, "\n"),
+		Data:    alerts,
+	}
+	// Вебхук или SMTP могут отвечать долго — запрос пользователя их не ждёт
+	go func() {
+		if err := s.Notifier.Notify(msg); err != nil {
+			s.Log.Error("Failed to deliver stock alert", "error", err.Error())
+		}
+	}()
+}
@@ -0,0 +1,118 @@
+package svc
+
+import (
+	"fmt"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetAvailability(menuItemID int) ([]models.AvailabilityWindow, error) {
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	windows, err := s.Repo.MenuRepo.GetAvailabilityWindowsByMenuItemID(menuItemID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve availability windows", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+	return windows, nil
+}
+
+func (s *svc) SetAvailability(menuItemID int, windows []models.AvailabilityWindow) ([]models.AvailabilityWindow, error) {
+	for _, w := range windows {
+		if err := helper.CheckTimeWindow(w.DaysOfWeek, w.StartTime, w.EndTime); err != nil {
+			s.Log.Error("Invalid availability window", "id", menuItemID, "error", err.Error())
+			return nil, err
+		}
+	}
+
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	if err := s.Repo.MenuRepo.SetAvailabilityWindows(menuItemID, windows); err != nil {
+		s.Log.Error("Failed to save availability windows", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Availability windows updated", "id", menuItemID, "count", len(windows))
+	return s.Repo.MenuRepo.GetAvailabilityWindowsByMenuItemID(menuItemID)
+}
+
+func (s *svc) GetPriceRules() ([]models.PriceRule, error) {
+	rules, err := s.Repo.PriceRuleRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve price rules", "error", err.Error())
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (s *svc) GetPriceRule(id int) (models.PriceRule, error) {
+	rule, err := s.Repo.PriceRuleRepo.GetByID(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve price rule", "id", id, "error", err.Error())
+		return models.PriceRule{}, err
+	}
+	return rule, nil
+}
+
+func (s *svc) CreatePriceRule(rule models.PriceRule) (*models.PriceRule, error) {
+	if rule.ID != 0 {
+		return nil, fmt.Errorf("price rule ID should not be set when adding a new rule")
+	}
+	if err := s.checkPriceRule(rule); err != nil {
+		return nil, err
+	}
+
+	created, err := s.Repo.PriceRuleRepo.Create(rule)
+	if err != nil {
+		s.Log.Error("Failed to create price rule", "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Price rule created", "id", created.ID, "name", created.Name)
+	return created, nil
+}
+
+func (s *svc) UpdatePriceRule(id int, rule models.PriceRule) error {
+	if err := s.checkPriceRule(rule); err != nil {
+		return err
+	}
+
+	if err := s.Repo.PriceRuleRepo.Update(id, rule); err != nil {
+		s.Log.Error("Failed to update price rule", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Price rule updated", "id", id)
+	return nil
+}
+
+func (s *svc) DeletePriceRule(id int) error {
+	if err := s.Repo.PriceRuleRepo.Delete(id); err != nil {
+		s.Log.Error("Failed to delete price rule", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Price rule deleted", "id", id)
+	return nil
+}
+
+func (s *svc) checkPriceRule(rule models.PriceRule) error {
+	if err := helper.CheckPriceRule(rule); err != nil {
+		s.Log.Error("Invalid price rule", "error", err.Error())
+		return err
+	}
+	if rule.MenuItemID != nil {
+		if _, err := s.Repo.MenuRepo.GetMenuItemByID(*rule.MenuItemID); err != nil {
+			s.Log.Error("Price rule targets unknown menu item", "menu_item_id", *rule.MenuItemID)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package svc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetCategories() ([]models.Category, error) {
+	categories, err := s.Repo.CategoryRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve categories", "error", err.Error())
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (s *svc) GetCategory(id int) (models.Category, error) {
+	category, err := s.Repo.CategoryRepo.GetByID(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve category", "id", id, "error", err.Error())
+		return models.Category{}, err
+	}
+	return category, nil
+}
+
+func (s *svc) CreateCategory(category models.Category) (*models.Category, error) {
+	if category.ID != 0 {
+		return nil, fmt.Errorf("category ID should not be set when adding a new category")
+	}
+	if err := s.checkCategory(0, &category); err != nil {
+		return nil, err
+	}
+
+	created, err := s.Repo.CategoryRepo.Create(category)
+	if err != nil {
+		s.Log.Error("Failed to create category", "name", category.Name, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Category created", "id", created.ID, "name", created.Name)
+	return created, nil
+}
+
+func (s *svc) UpdateCategory(id int, category models.Category) error {
+	if err := s.checkCategory(id, &category); err != nil {
+		return err
+	}
+
+	if err := s.Repo.CategoryRepo.Update(id, category); err != nil {
+		s.Log.Error("Failed to update category", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Category updated", "id", id)
+	return nil
+}
+
+func (s *svc) DeleteCategory(id int) error {
+	if err := s.Repo.CategoryRepo.Delete(id); err != nil {
+		s.Log.Error("Failed to delete category", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Category deleted", "id", id)
+	return nil
+}
+
+// checkCategory нормализует имя и не даёт сделать категорию потомком самой себя.
+func (s *svc) checkCategory(id int, category *models.Category) error {
+	category.Name = strings.ToLower(strings.TrimSpace(category.Name))
+	if category.Name == "" {
+		s.Log.Error("Invalid category name")
+		return fmt.Errorf("please provide a name for the category")
+	}
+	if category.ParentID == nil {
+		return nil
+	}
+
+	categories, err := s.Repo.CategoryRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve categories", "error", err.Error())
+		return err
+	}
+	parents := make(map[int]*int, len(categories))
+	for _, c := range categories {
+		parents[c.ID] = c.ParentID
+	}
+
+	if _, ok := parents[*category.ParentID]; !ok {
+		s.Log.Error("Parent category not found", "parent_id", *category.ParentID)
+		return fmt.Errorf("parent category with ID %d does not exist", *category.ParentID)
+	}
+	for p := category.ParentID; p != nil; p = parents[*p] {
+		if *p == id {
+			s.Log.Error("Category cycle detected", "id", id, "parent_id", *category.ParentID)
+			return fmt.Errorf("category %d cannot be nested under its own subcategory", id)
+		}
+	}
+	return nil
+}
+
+// resolveCategories связывает позицию с категориями. Категории можно передать
+// по id (category_ids) или по имени (categories); имена сравниваются без учёта регистра.
+func (s *svc) resolveCategories(item *models.MenuItem) error {
+	categories, err := s.Repo.CategoryRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve categories", "error", err.Error())
+		return err
+	}
+	byID := make(map[int]models.Category, len(categories))
+	byName := make(map[string]models.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+		byName[strings.ToLower(c.Name)] = c
+	}
+
+	seen := make(map[int]bool)
+	var ids []int
+	var names []string
+	add := func(c models.Category) {
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			ids = append(ids, c.ID)
+			names = append(names, c.Name)
+		}
+	}
+
+	for _, id := range item.CategoryIDs {
+		c, ok := byID[id]
+		if !ok {
+			s.Log.Error("Unknown category", "category_id", id)
+			return fmt.Errorf("category with ID %d does not exist", id)
+		}
+		add(c)
+	}
+	for _, name := range item.Categories {
+		c, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			s.Log.Error("Unknown category", "category", name)
+			return fmt.Errorf("category %q does not exist, create it via /categories first", name)
+		}
+		add(c)
+	}
+
+	item.CategoryIDs = ids
+	item.Categories = names
+	return nil
+}
+
+// categoryWithDescendants находит категорию по id или имени и возвращает её вместе со всеми подкатегориями.
+func (s *svc) categoryWithDescendants(value string) (map[int]bool, error) {
+	categories, err := s.Repo.CategoryRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve categories", "error", err.Error())
+		return nil, err
+	}
+
+	root := -1
+	id, convErr := strconv.Atoi(value)
+	for _, c := range categories {
+		if (convErr == nil && c.ID == id) || strings.EqualFold(c.Name, strings.TrimSpace(value)) {
+			root = c.ID
+			break
+		}
+	}
+	if root == -1 {
+		return nil, fmt.Errorf("category %q does not exist", value)
+	}
+
+	result := map[int]bool{root: true}
+	for changed := true; changed; {
+		changed = false
+		for _, c := range categories {
+			if c.ParentID != nil && result[*c.ParentID] && !result[c.ID] {
+				result[c.ID] = true
+				changed = true
+			}
+		}
+	}
+	return result, nil
+}
+
+func inCategories(item models.MenuItem, categories map[int]bool) bool {
+	for _, id := range item.CategoryIDs {
+		if categories[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMenuTree группирует меню по дереву категорий в порядке display_order.
+// Позиция с несколькими категориями показывается в каждой из них.
+func (s *svc) GetMenuTree(filter models.MenuFilter) (*models.MenuTree, error) {
+	items, err := s.ListMenu(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.Repo.CategoryRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve categories", "error", err.Error())
+		return nil, err
+	}
+
+	itemsByCategory := make(map[int][]models.MenuItem)
+	tree := &models.MenuTree{Categories: []models.CategoryNode{}, Uncategorized: []models.MenuItem{}}
+	for _, item := range items {
+		if len(item.CategoryIDs) == 0 {
+			tree.Uncategorized = append(tree.Uncategorized, item)
+			continue
+		}
+		for _, id := range item.CategoryIDs {
+			itemsByCategory[id] = append(itemsByCategory[id], item)
+		}
+	}
+
+	var build func(parentID *int) []models.CategoryNode
+	build = func(parentID *int) []models.CategoryNode {
+		nodes := []models.CategoryNode{}
+		for _, c := range categories {
+			if (parentID == nil) != (c.ParentID == nil) || (parentID != nil && *parentID != *c.ParentID) {
+				continue
+			}
+			id := c.ID
+			node := models.CategoryNode{Category: c, Children: build(&id), Items: itemsByCategory[c.ID]}
+			if node.Items == nil {
+				node.Items = []models.MenuItem{}
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	tree.Categories = build(nil)
+
+	s.Log.Info("Menu tree built", "categories", len(categories), "items", len(items))
+	return tree, nil
+}
@@ -0,0 +1,92 @@
+package svc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+const (
+	defaultForecastDays  = 7
+	defaultForecastWeeks = 4
+)
+
+// GetIngredientForecast прогнозирует расход ингредиентов на days дней вперёд по
+// продажам за weeks последних недель (см. helper.ForecastMenuDemand).
+func (s *svc) GetIngredientForecast(days, weeks int) (*models.ForecastReport, error) {
+	if days == 0 {
+		days = defaultForecastDays
+	}
+	if weeks == 0 {
+		weeks = defaultForecastWeeks
+	}
+	if days < 0 || days > 90 {
+		return nil, fmt.Errorf("days must be between 1 and 90, got: %d", days)
+	}
+	if weeks < 0 || weeks > 52 {
+		return nil, fmt.Errorf("weeks must be between 1 and 52, got: %d", weeks)
+	}
+
+	now := time.Now()
+	// Окно — ровно weeks недель до начала текущего часа, чтобы каждый день недели
+	// и час встречались в нём weeks раз
+	until := now.Truncate(time.Hour)
+	history, err := s.Repo.OrderRepo.GetSalesByWeekdayHour(until.AddDate(0, 0, -7*weeks), until)
+	if err != nil {
+		s.Log.Error("Failed to retrieve sales history", "error", err.Error())
+		return nil, err
+	}
+
+	inventoryMap, err := s.inventoryMap()
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := s.recipes(inventoryMap)
+	if err != nil {
+		return nil, err
+	}
+
+	demand := helper.ForecastMenuDemand(history, weeks, now, days)
+	usage := helper.ForecastIngredients(demand, recipes)
+
+	report := &models.ForecastReport{From: now, Days: days, Weeks: weeks, Ingredients: []models.IngredientForecast{}}
+	for id, daily := range usage {
+		item, ok := inventoryMap[id]
+		if !ok {
+			continue
+		}
+		forecast := models.IngredientForecast{
+			IngredientID: id,
+			Name:         item.Name,
+			Unit:         item.Unit,
+			Stock:        item.Stock,
+			Daily:        make([]models.DailyQuantity, days),
+		}
+		for i, quantity := range daily {
+			date := now.AddDate(0, 0, i).Format(time.DateOnly)
+			forecast.Daily[i] = models.DailyQuantity{Date: date, Quantity: quantity}
+			forecast.Total += quantity
+			if forecast.RunsOutOn == nil && forecast.Total > item.Stock {
+				forecast.RunsOutOn = &date
+			}
+		}
+		forecast.Total = math.Round(forecast.Total*10000) / 10000
+		report.Ingredients = append(report.Ingredients, forecast)
+	}
+
+	sort.Slice(report.Ingredients, func(i, j int) bool {
+		a, b := report.Ingredients[i], report.Ingredients[j]
+		if (a.RunsOutOn == nil) != (b.RunsOutOn == nil) {
+			return a.RunsOutOn != nil
+		}
+		if a.RunsOutOn != nil && *a.RunsOutOn != *b.RunsOutOn {
+			return *a.RunsOutOn < *b.RunsOutOn
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
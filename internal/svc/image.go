@@ -0,0 +1,201 @@
+package svc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	"frappuccino/internal/models"
+	"frappuccino/pkg/thumbnail"
+)
+
+const (
+	MaxImageSize  = 10 << 20
+	thumbnailSize = 320
+)
+
+// UploadMenuItemImages сохраняет изображения позиции вместе с миниатюрами. Сначала
+// проверяются и уменьшаются все файлы, и только потом что-то сохраняется; если
+// сохранить не удалось, уже сохранённые изображения убираются — либо все, либо ни одного.
+func (s *svc) UploadMenuItemImages(menuItemID int, files []models.ImageUpload) ([]models.MenuItemImage, error) {
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	type prepared struct {
+		img   models.MenuItemImage
+		data  []byte
+		thumb []byte
+	}
+	images := make([]prepared, 0, len(files))
+	for _, f := range files {
+		img, thumb, err := s.prepareImage(menuItemID, f.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		images = append(images, prepared{img: img, data: f.Data, thumb: thumb})
+	}
+
+	created := make([]models.MenuItemImage, 0, len(images))
+	for _, p := range images {
+		img, err := s.storeImage(p.img, p.data, p.thumb)
+		if err != nil {
+			for _, done := range created {
+				if err := s.DeleteMenuItemImage(menuItemID, done.ID); err != nil {
+					s.Log.Warn("Failed to remove partially uploaded image", "id", menuItemID, "image_id", done.ID, "error", err.Error())
+				}
+			}
+			return nil, err
+		}
+		created = append(created, *img)
+	}
+
+	s.Log.Info("Menu item images uploaded", "id", menuItemID, "count", len(created))
+	return s.withImageURLs(created), nil
+}
+
+// prepareImage проверяет файл и делает миниатюру, ничего не сохраняя.
+func (s *svc) prepareImage(menuItemID int, data []byte) (models.MenuItemImage, []byte, error) {
+	if len(data) == 0 {
+		return models.MenuItemImage{}, nil, fmt.Errorf("image file is empty")
+	}
+	if len(data) > MaxImageSize {
+		return models.MenuItemImage{}, nil, fmt.Errorf("image must not exceed %d MB", MaxImageSize>>20)
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := thumbnail.Formats[contentType]
+	if !ok {
+		s.Log.Error("Unsupported image type", "id", menuItemID, "content_type", contentType)
+		return models.MenuItemImage{}, nil, fmt.Errorf("unsupported image type %q: use JPEG, PNG or GIF", contentType)
+	}
+
+	thumb, thumbType, err := thumbnail.Make(data, thumbnailSize)
+	if err != nil {
+		s.Log.Error("Failed to create thumbnail", "id", menuItemID, "error", err.Error())
+		return models.MenuItemImage{}, nil, err
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return models.MenuItemImage{}, nil, fmt.Errorf("failed to generate file name: %v", err)
+	}
+	base := fmt.Sprintf("menu-%d-%s", menuItemID, hex.EncodeToString(suffix))
+	img := models.MenuItemImage{
+		MenuItemID:    menuItemID,
+		FileName:      base + ext,
+		ThumbnailName: base + "-thumb" + thumbnail.Formats[thumbType],
+		ContentType:   contentType,
+	}
+	return img, thumb, nil
+}
+
+// storeImage сохраняет файлы и запись об изображении; при ошибке файлы убираются.
+func (s *svc) storeImage(img models.MenuItemImage, data, thumb []byte) (*models.MenuItemImage, error) {
+	if err := s.Media.Save(img.FileName, bytes.NewReader(data)); err != nil {
+		s.Log.Error("Failed to store image", "id", img.MenuItemID, "error", err.Error())
+		return nil, err
+	}
+	if err := s.Media.Save(img.ThumbnailName, bytes.NewReader(thumb)); err != nil {
+		s.Log.Error("Failed to store thumbnail", "id", img.MenuItemID, "error", err.Error())
+		s.removeImageFiles(img)
+		return nil, err
+	}
+
+	created, err := s.Repo.MenuRepo.CreateImage(img)
+	if err != nil {
+		s.Log.Error("Failed to save image", "id", img.MenuItemID, "error", err.Error())
+		s.removeImageFiles(img)
+		return nil, err
+	}
+
+	s.Log.Info("Menu item image uploaded", "id", img.MenuItemID, "image_id", created.ID, "size", len(data))
+	return created, nil
+}
+
+func (s *svc) GetMenuItemImages(menuItemID int) ([]models.MenuItemImage, error) {
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	images, err := s.Repo.MenuRepo.GetImagesByMenuItemID(menuItemID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve images", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+	return s.withImageURLs(images), nil
+}
+
+func (s *svc) DeleteMenuItemImage(menuItemID, imageID int) error {
+	img, err := s.Repo.MenuRepo.DeleteImage(menuItemID, imageID)
+	if err != nil {
+		s.Log.Error("Failed to delete image", "id", menuItemID, "image_id", imageID, "error", err.Error())
+		return err
+	}
+
+	s.removeImageFiles(*img)
+	s.Log.Info("Menu item image deleted", "id", menuItemID, "image_id", imageID)
+	return nil
+}
+
+// OpenMedia отдаёт файл из хранилища для GET /media/{name}.
+func (s *svc) OpenMedia(name string) (io.ReadSeekCloser, error) {
+	return s.Media.Open(name)
+}
+
+func (s *svc) withImageURLs(images []models.MenuItemImage) []models.MenuItemImage {
+	for i := range images {
+		images[i].URL = s.Media.URL(images[i].FileName)
+		images[i].ThumbnailURL = s.Media.URL(images[i].ThumbnailName)
+	}
+	return images
+}
+
+// imageSnapshot запоминает изображения меню перед изменением, после которого
+// позиции могут быть удалены (публикация черновика, откат, импорт, DELETE /menu).
+// При ошибке возвращает nil: файлы тогда просто не будут убраны.
+func (s *svc) imageSnapshot() map[int]models.MenuItemImage {
+	images, err := s.Repo.MenuRepo.GetImages()
+	if err != nil {
+		s.Log.Error("Failed to retrieve images", "error", err.Error())
+		return nil
+	}
+	snapshot := make(map[int]models.MenuItemImage)
+	for _, list := range images {
+		for _, img := range list {
+			snapshot[img.ID] = img
+		}
+	}
+	return snapshot
+}
+
+// removeDroppedImages удаляет файлы изображений из снимка, чьи записи исчезли
+// вместе с позициями (каскадом в базе).
+func (s *svc) removeDroppedImages(before map[int]models.MenuItemImage) {
+	if len(before) == 0 {
+		return
+	}
+	after := s.imageSnapshot()
+	if after == nil {
+		return
+	}
+	for id, img := range before {
+		if _, ok := after[id]; !ok {
+			s.removeImageFiles(img)
+		}
+	}
+}
+
+// removeImageFiles не возвращает ошибку: запись уже удалена, потерянный файл только занимает место.
+func (s *svc) removeImageFiles(img models.MenuItemImage) {
+	for _, name := range []string{img.FileName, img.ThumbnailName} {
+		if err := s.Media.Delete(name); err != nil {
+			s.Log.Warn("Failed to delete media file", "file", name, "error", err.Error())
+		}
+	}
+}
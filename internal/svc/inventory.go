@@ -5,39 +5,61 @@ import (
 
 	"frappuccino/helper"
 	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+	"frappuccino/pkg/units"
 )
 
 func (s *svc) CreateInventory(data models.InventoryItem) error {
 	if err := helper.IsValidName(data.Name); err != nil {
-		s.Log.Error(err.Error(), "Invalid inventory name")
+		s.Log.Error("Invalid inventory name", "error", err.Error())
 		return err
 	}
 
 	if err := helper.CheckerForInventItems(data); err != nil {
-		s.Log.Error(err.Error(), "Invalid inventory data")
+		s.Log.Error("Invalid inventory data", "error", err.Error())
 		return err
 	}
+	// Храним каноническое имя единицы: "grams" и "G" превращаются в "g"
+	unit, _ := units.Parse(data.Unit)
+	data.Unit = unit.Name
 
 	// Проверяем по Name и Unit
 	existingItem, err := s.Repo.InventoryRepo.GetByNameAndUnit(data.Name, data.Unit)
 	if err == nil {
-		existingItem.Stock += data.Stock
-		if err := s.Repo.InventoryRepo.PutInventory(existingItem.ID, existingItem); err != nil {
-			s.Log.Error(err.Error(), "Failed to update existing inventory item")
+		if data.Nutrition != nil {
+			existingItem.Nutrition = data.Nutrition
+			if err := s.Repo.InventoryRepo.PutInventory(existingItem.ID, existingItem, models.DefaultLocationID); err != nil {
+				s.Log.Error("Failed to update existing inventory item", "error", err.Error())
+				return err
+			}
+		}
+
+		// Повторное добавление той же позиции — это поставка
+		t, err := s.Repo.InventoryRepo.RecordTransaction(models.InventoryTransaction{
+			IngredientID: existingItem.ID,
+			ChangeAmount: data.Stock,
+			Type:         models.TransactionPurchase,
+		})
+		if err != nil {
+			s.Log.Error("Failed to update existing inventory item", "error", err.Error())
 			return err
 		}
-		s.Log.Info("Inventory item quantity updated", "id", existingItem.ID, "new_stock", existingItem.Stock)
+
+		s.syncStockAvailability()
+		s.Log.Info("Inventory item quantity updated", "id", existingItem.ID, "new_stock", t.Balance)
 		return nil
 	} else if err.Error() != fmt.Sprintf("item with name %s and unit %s not found", data.Name, data.Unit) {
-		s.Log.Error(err.Error(), "Error checking inventory item existence")
+		s.Log.Error("Error checking inventory item existence", "error", err.Error())
 		return err
 	}
 
 	if err := s.Repo.InventoryRepo.CreateInventory(data); err != nil {
-		s.Log.Error(err.Error(), "Failed to create inventory item")
+		s.Log.Error("Failed to create inventory item", "error", err.Error())
 		return err
 	}
 
+	s.syncStockAvailability()
+
 	s.Log.Info("Inventory item created successfully", "id", data.ID)
 	return nil
 }
@@ -45,7 +67,7 @@ func (s *svc) CreateInventory(data models.InventoryItem) error {
 func (s *svc) InventoriesGet() ([]models.InventoryItem, error) {
 	data, err := s.Repo.InventoryRepo.GetInventory()
 	if err != nil {
-		s.Log.Error(err.Error(), "Failed to get inventories")
+		s.Log.Error("Failed to get inventories", "error", err.Error())
 		return nil, err
 	}
 
@@ -56,7 +78,7 @@ func (s *svc) InventoriesGet() ([]models.InventoryItem, error) {
 func (s *svc) InventoryGetId(id int) (models.InventoryItem, error) {
 	data, err := s.Repo.InventoryRepo.GetInventoryId(id)
 	if err != nil {
-		s.Log.Error(err.Error(), "Failed to fetch inventory item by ID")
+		s.Log.Error("Failed to fetch inventory item by ID", "error", err.Error())
 		return models.InventoryItem{}, err
 	}
 
@@ -64,54 +86,147 @@ func (s *svc) InventoryGetId(id int) (models.InventoryItem, error) {
 	return data, nil
 }
 
-func (s *svc) InventoryUpDate(id int, data models.InventoryItem) error {
+// InventoryUpDate обновляет позицию; изменение остатка проводится в локации
+// locationID (0 — основная).
+func (s *svc) InventoryUpDate(id int, data models.InventoryItem, locationID int) error {
 	if err := helper.IsValidName(data.Name); err != nil {
-		s.Log.Error(err.Error(), "Invalid inventory name")
+		s.Log.Error("Invalid inventory name", "error", err.Error())
 		return err
 	}
 
 	if err := helper.CheckerForInventItems(data); err != nil {
-		s.Log.Error(err.Error(), "Invalid inventory data")
+		s.Log.Error("Invalid inventory data", "error", err.Error())
+		return err
+	}
+	// Храним каноническое имя единицы: "grams" и "G" превращаются в "g"
+	unit, _ := units.Parse(data.Unit)
+	data.Unit = unit.Name
+
+	// Рецептуры, где используется ингредиент, должны пересчитываться в новую единицу
+	recipes, err := s.Repo.MenuRepo.GetAllIngredients()
+	if err != nil {
+		s.Log.Error("Failed to retrieve recipes", "error", err.Error())
+		return err
+	}
+	for menuItemID, ingredients := range recipes {
+		for _, ing := range ingredients {
+			if ing.IngredientID != id {
+				continue
+			}
+			if _, err := helper.RecipeQuantity(ing, data); err != nil {
+				s.Log.Error("Inventory unit conflicts with recipe", "id", id, "menu_item_id", menuItemID, "error", err.Error())
+				return fmt.Errorf("menu item %d: %w", menuItemID, err)
+			}
+		}
+	}
+
+	old, err := s.Repo.InventoryRepo.GetInventoryId(id)
+	if err != nil {
+		s.Log.Error("Failed to fetch inventory item by ID", "id", id, "error", err.Error())
 		return err
 	}
 
-	if err := s.Repo.InventoryRepo.PutInventory(id, data); err != nil {
-		s.Log.Error(err.Error(), "Failed to update inventory item")
+	if err := s.checkLocation(&locationID); err != nil {
+		s.Log.Error("Invalid location", "location_id", locationID, "error", err.Error())
 		return err
 	}
 
+	if err := s.Repo.InventoryRepo.PutInventory(id, data, locationID); err != nil {
+		s.Log.Error("Failed to update inventory item", "error", err.Error())
+		return err
+	}
+
+	s.syncStockAvailability()
+	s.notifyLowStock(map[int]float64{id: data.Stock - old.Stock})
+
 	s.Log.Info("Inventory item updated successfully", "id", id)
 	return nil
 }
 
 func (s *svc) DeleteInvent(id int) error {
 	if err := s.Repo.InventoryRepo.DeleteInvent(id); err != nil {
-		s.Log.Error(err.Error(), "Failed to delete inventory item")
+		s.Log.Error("Failed to delete inventory item", "error", err.Error())
 		return err
 	}
 
+	s.syncStockAvailability()
+
 	s.Log.Info("Inventory item deleted successfully", "id", id)
 	return nil
 }
 
-func (s *svc) GetLeftOvers(sortBy string, page int, pageSize int) (*models.InventoryResponse, error) {
-	items, err := s.Repo.InventoryRepo.GetLeftOversWithPagination(sortBy, page, pageSize)
+// GetLeftOvers возвращает страницу остатков по фильтру. Курсор из nextCursor
+// продолжает выдачу с места, где закончилась страница, и не сбивается, если
+// между запросами позиции добавили или удалили.
+func (s *svc) GetLeftOvers(filter models.LeftOverFilter) (*models.InventoryResponse, error) {
+	if filter.SortBy == "" {
+		filter.SortBy = "name"
+	}
+	switch filter.SortBy {
+	case "name", "price", "quantity", "value":
+	default:
+		return nil, fmt.Errorf("%w: sortBy must be name, price, quantity or value, got: %s", cerrors.ErrInvalidParam, filter.SortBy)
+	}
+	if filter.SortOrder == "" {
+		filter.SortOrder = "asc"
+	}
+	if filter.SortOrder != "asc" && filter.SortOrder != "desc" {
+		return nil, fmt.Errorf("%w: sortOrder must be asc or desc, got: %s", cerrors.ErrInvalidParam, filter.SortOrder)
+	}
+	if filter.Unit != "" {
+		unit, err := units.Parse(filter.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v, see GET /units for supported units", cerrors.ErrInvalidParam, err)
+		}
+		filter.Unit = unit.Name
+	}
+	if filter.MinStock != nil && filter.MaxStock != nil && *filter.MinStock > *filter.MaxStock {
+		return nil, fmt.Errorf("%w: minStock must not be greater than maxStock", cerrors.ErrInvalidParam)
+	}
+	if filter.Cursor != "" {
+		if filter.Page > 1 {
+			return nil, fmt.Errorf("%w: page and cursor cannot be used together", cerrors.ErrInvalidParam)
+		}
+		after, err := helper.DecodeLeftOverCursor(filter.Cursor, filter.SortBy, filter.SortOrder)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", cerrors.ErrInvalidParam, err)
+		}
+		filter.After, filter.Page = after, 0
+	}
+
+	items, keys, err := s.Repo.InventoryRepo.GetLeftOversWithPagination(filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get inventory leftovers: %w", err)
 	}
 
-	totalItems, err := s.Repo.InventoryRepo.CountTotalInventoryItems()
+	totalItems, err := s.Repo.InventoryRepo.CountLeftOvers(filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count inventory items: %w", err)
 	}
 
-	totalPages := (totalItems + pageSize - 1) / pageSize
-
-	return &models.InventoryResponse{
-		CurrentPage: page,
-		PageSize:    pageSize,
-		HasNextPage: page*pageSize < totalItems,
-		TotalPages:  totalPages,
-		Data:        items,
-	}, nil
+	response := &models.InventoryResponse{
+		PageSize:   filter.PageSize,
+		TotalItems: totalItems,
+		Data:       []models.LeftOverItem{},
+	}
+	// Номера страниц имеют смысл только без курсора
+	if filter.After == nil {
+		totalPages := (totalItems + filter.PageSize - 1) / filter.PageSize
+		response.CurrentPage, response.TotalPages = &filter.Page, &totalPages
+	}
+	if len(items) > filter.PageSize {
+		items, keys = items[:filter.PageSize], keys[:filter.PageSize]
+		last := items[len(items)-1]
+		response.HasNextPage = true
+		response.NextCursor = helper.EncodeLeftOverCursor(models.LeftOverCursor{
+			SortBy:    filter.SortBy,
+			SortOrder: filter.SortOrder,
+			Value:     keys[len(keys)-1],
+			ID:        last.ID,
+		})
+	}
+	if items != nil {
+		response.Data = items
+	}
+	return response, nil
 }
@@ -0,0 +1,269 @@
+package svc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+	"frappuccino/pkg/units"
+)
+
+// ExportInventory выгружает склад в формате, который принимает ImportInventory.
+func (s *svc) ExportInventory() ([]models.InventoryExportItem, error) {
+	items, err := s.Repo.InventoryRepo.GetInventory()
+	if err != nil {
+		s.Log.Error("Failed to get inventories", "error", err.Error())
+		return nil, err
+	}
+
+	result := make([]models.InventoryExportItem, 0, len(items))
+	for _, item := range items {
+		threshold, price := item.ReorderThreshold, item.Price
+		result = append(result, models.InventoryExportItem{
+			ID:               item.ID,
+			Name:             item.Name,
+			Stock:            item.Stock,
+			Unit:             item.Unit,
+			ReorderThreshold: &threshold,
+			Price:            &price,
+		})
+	}
+
+	s.Log.Info("Inventory exported", "count", len(result))
+	return result, nil
+}
+
+// ImportInventory разбирает файл в формате json или csv и применяет его одной
+// транзакцией. Позиции ищутся по имени без учёта регистра: найденные
+// обновляются, остальные создаются. Строки проверяются так же, как POST /inventory;
+// если хоть одна строка отклонена или задан dryRun, склад не меняется и
+// возвращается только отчёт. locationID — где проводится поставка (add) или
+// корректировка остатка (set).
+func (s *svc) ImportInventory(data io.Reader, format, mode string, locationID int, dryRun bool) (*models.InventoryImportResult, error) {
+	switch mode {
+	case "":
+		mode = models.InventoryImportSet
+	case models.InventoryImportSet, models.InventoryImportAdd:
+	default:
+		return nil, fmt.Errorf("import mode must be set or add, got: %q", mode)
+	}
+	if err := s.checkLocation(&locationID); err != nil {
+		return nil, err
+	}
+
+	var records []models.InventoryExportItem
+	var rowErrors []error
+	switch format {
+	case "json":
+		if err := json.NewDecoder(data).Decode(&records); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		rowErrors = make([]error, len(records))
+	case "csv":
+		var err error
+		if records, rowErrors, err = helper.ReadInventoryCSV(data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("import format must be json or csv, got: %q", format)
+	}
+
+	existing, err := s.Repo.InventoryRepo.GetInventory()
+	if err != nil {
+		s.Log.Error("Failed to get inventories", "error", err.Error())
+		return nil, err
+	}
+	byName := make(map[string]models.InventoryItem, len(existing))
+	for _, item := range existing {
+		byName[strings.ToLower(item.Name)] = item
+	}
+
+	result := &models.InventoryImportResult{
+		Mode:      mode,
+		DryRun:    dryRun,
+		Created:   []models.InventoryImportRow{},
+		Updated:   []models.InventoryImportRow{},
+		Unchanged: []models.InventoryImportRow{},
+		Rejected:  []models.InventoryImportRow{},
+	}
+	var changes []models.InventoryImportChange
+	stockChanges := make(map[int]float64)
+	seenNames := make(map[string]int)
+
+	for i, rec := range records {
+		row := models.InventoryImportRow{Row: i + 1, IngredientID: rec.ID, Name: rec.Name}
+		change, old, found, err := s.inventoryImportChange(rec, rowErrors[i], mode, byName)
+		if err == nil {
+			key := strings.ToLower(change.Item.Name)
+			if prev, dup := seenNames[key]; dup {
+				err = fmt.Errorf("duplicate name %q, already used in row %d", rec.Name, prev)
+			}
+			seenNames[key] = row.Row
+		}
+		if err != nil {
+			row.Action, row.Error = models.ImportActionReject, err.Error()
+			result.Rejected = append(result.Rejected, row)
+			continue
+		}
+		change.LocationID = locationID
+
+		if !found {
+			row.Action = models.ImportActionCreate
+			result.Created = append(result.Created, row)
+			changes = append(changes, change)
+			continue
+		}
+
+		row.IngredientID, row.Name = old.ID, old.Name
+		row.Changes = diffInventoryItem(old, change.Item)
+		if len(row.Changes) == 0 {
+			row.Action, row.Changes = models.ImportActionUnchanged, nil
+			result.Unchanged = append(result.Unchanged, row)
+			continue
+		}
+		row.Action = models.ImportActionUpdate
+		result.Updated = append(result.Updated, row)
+		changes = append(changes, change)
+		stockChanges[old.ID] = change.Item.Stock - old.Stock
+	}
+
+	s.Log.Info("Inventory import checked", "rows", len(records), "mode", mode, "created", len(result.Created),
+		"updated", len(result.Updated), "unchanged", len(result.Unchanged), "rejected", len(result.Rejected), "dry_run", dryRun)
+
+	if dryRun || len(result.Rejected) > 0 || len(changes) == 0 {
+		return result, nil
+	}
+
+	if err := s.Repo.InventoryRepo.ImportInventory(changes); err != nil {
+		s.Log.Error("Failed to import inventory", "error", err.Error())
+		return nil, err
+	}
+	result.Applied = true
+
+	s.syncStockAvailability()
+	s.notifyLowStock(stockChanges)
+
+	s.Log.Info("Inventory imported", "changes", len(changes), "mode", mode)
+	return result, nil
+}
+
+// inventoryImportChange проверяет строку импорта и превращает её в изменение
+// склада. Если позиция уже есть и единица строки другая, количества и цена
+// пересчитываются в единицу позиции: единицу меняет только PUT /inventory/{id},
+// где проверяются рецептуры.
+func (s *svc) inventoryImportChange(rec models.InventoryExportItem, rowErr error, mode string, byName map[string]models.InventoryItem) (change models.InventoryImportChange, old models.InventoryItem, found bool, err error) {
+	if rowErr != nil {
+		return change, old, false, rowErr
+	}
+
+	name := strings.TrimSpace(rec.Name)
+	old, found = byName[strings.ToLower(name)]
+	if rec.ID != 0 && (!found || old.ID != rec.ID) {
+		return change, old, false, fmt.Errorf("id %d does not match the inventory item named %q", rec.ID, name)
+	}
+
+	item := models.InventoryItem{Name: name, Stock: rec.Stock, Unit: rec.Unit}
+	if found {
+		item.Name, item.ReorderThreshold, item.Price = old.Name, old.ReorderThreshold, old.Price
+		if item.Unit == "" {
+			item.Unit = old.Unit
+		}
+	}
+	if rec.ReorderThreshold != nil {
+		item.ReorderThreshold = *rec.ReorderThreshold
+	}
+	if rec.Price != nil {
+		item.Price = *rec.Price
+	}
+
+	if err := helper.IsValidName(item.Name); err != nil {
+		return change, old, found, err
+	}
+	if err := helper.CheckImportedInventItem(item, mode == models.InventoryImportAdd); err != nil {
+		return change, old, found, err
+	}
+	if item.ReorderThreshold < 0 {
+		return change, old, found, fmt.Errorf("reorder_threshold must not be negative, got: %v", item.ReorderThreshold)
+	}
+	if item.Price < 0 {
+		return change, old, found, fmt.Errorf("price must not be negative, got: %v", item.Price)
+	}
+	// Храним каноническое имя единицы: "grams" и "G" превращаются в "g"
+	unit, _ := units.Parse(item.Unit)
+	item.Unit = unit.Name
+
+	if !found {
+		if subCent(item.Price) {
+			return change, old, found, fmt.Errorf("price %v has more than 2 decimals, but inventory prices are kept in cents", item.Price)
+		}
+		change.Item = item
+		if mode == models.InventoryImportAdd {
+			delivered := item.Stock
+			change.Delivered = &delivered
+		}
+		return change, old, false, nil
+	}
+
+	if item.Unit != old.Unit {
+		factor, err := units.Convert(1, item.Unit, old.Unit)
+		if err != nil {
+			return change, old, found, fmt.Errorf("unit %q cannot be converted to %q of %s: %w", item.Unit, old.Unit, old.Name, err)
+		}
+		item.Stock *= factor
+		if rec.ReorderThreshold != nil {
+			item.ReorderThreshold *= factor
+		}
+		if rec.Price != nil {
+			item.Price /= factor
+		}
+		item.Unit = old.Unit
+	}
+	// Цена позиции хранится в центах: 3.50 за kg — это 0.0035 за g. В поставку
+	// такая цена идёт закупочной с полной точностью, а цену позиции не меняет.
+	if rec.Price != nil && subCent(item.Price) {
+		if mode != models.InventoryImportAdd {
+			return change, old, found, fmt.Errorf("price %v per %s has more than 2 decimals, but inventory prices are kept in cents; use mode=add to record it as the delivery cost only",
+				item.Price, old.Unit)
+		}
+		unitCost := item.Price
+		change.UnitCost = &unitCost
+		item.Price = old.Price
+	}
+	// Остатки хранятся с четырьмя знаками, цены — с двумя
+	item.Stock = math.Round(item.Stock*10000) / 10000
+	item.ReorderThreshold = math.Round(item.ReorderThreshold*100) / 100
+	item.Price = math.Round(item.Price*100) / 100
+
+	item.ID = old.ID
+	if mode == models.InventoryImportAdd {
+		delivered := item.Stock
+		change.Delivered = &delivered
+		item.Stock = old.Stock + delivered
+	}
+	change.Item = item
+	return change, old, found, nil
+}
+
+// diffInventoryItem возвращает изменившиеся поля позиции склада.
+func diffInventoryItem(old, item models.InventoryItem) map[string]models.FieldChange {
+	changes := make(map[string]models.FieldChange)
+	if old.Stock != item.Stock {
+		changes["stock"] = models.FieldChange{Old: old.Stock, New: item.Stock}
+	}
+	if old.ReorderThreshold != item.ReorderThreshold {
+		changes["reorder_threshold"] = models.FieldChange{Old: old.ReorderThreshold, New: item.ReorderThreshold}
+	}
+	if old.Price != item.Price {
+		changes["price"] = models.FieldChange{Old: old.Price, New: item.Price}
+	}
+	return changes
+}
+
+// subCent сообщает, что цену нельзя сохранить в центах без потерь.
+func subCent(price float64) bool {
+	return math.Abs(price*100-math.Round(price*100)) > 1e-6
+}
@@ -0,0 +1,81 @@
+package svc
+
+import (
+	"math"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetInventoryTransactions(ingredientID int, filter models.TransactionFilter) ([]models.InventoryTransaction, error) {
+	if filter.Type != "" {
+		if err := helper.CheckTransactionType(filter.Type); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.Repo.InventoryRepo.GetInventoryId(ingredientID); err != nil {
+		s.Log.Error("Failed to fetch inventory item by ID", "id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+
+	transactions, err := s.Repo.InventoryRepo.GetTransactions(ingredientID, filter)
+	if err != nil {
+		s.Log.Error("Failed to retrieve inventory transactions", "id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// RecordInventoryTransaction проводит движение остатка через журнал.
+func (s *svc) RecordInventoryTransaction(ingredientID int, t models.InventoryTransaction) (*models.InventoryTransaction, error) {
+	t.IngredientID = ingredientID
+	t.OrderID = nil
+	t.PurchaseOrderID = nil
+	t.TransferID = nil
+	if err := helper.CheckTransaction(t); err != nil {
+		s.Log.Error("Invalid inventory transaction", "id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+	if err := s.checkLocation(&t.LocationID); err != nil {
+		s.Log.Error("Invalid inventory transaction location", "id", ingredientID, "location_id", t.LocationID, "error", err.Error())
+		return nil, err
+	}
+
+	recorded, err := s.Repo.InventoryRepo.RecordTransaction(t)
+	if err != nil {
+		s.Log.Error("Failed to record inventory transaction", "id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+
+	s.syncStockAvailability()
+	s.notifyLowStock(map[int]float64{ingredientID: recorded.ChangeAmount})
+
+	s.Log.Info("Inventory transaction recorded", "id", ingredientID, "type", recorded.Type,
+		"change", recorded.ChangeAmount, "balance", recorded.Balance)
+	return recorded, nil
+}
+
+// CheckInventoryLedger сверяет остатки с журналом: у каждого ингредиента сумма
+// движений должна совпадать с inventory.stock.
+func (s *svc) CheckInventoryLedger() (*models.LedgerCheck, error) {
+	balances, err := s.Repo.InventoryRepo.GetLedgerBalances()
+	if err != nil {
+		s.Log.Error("Failed to retrieve ledger balances", "error", err.Error())
+		return nil, err
+	}
+
+	check := &models.LedgerCheck{Checked: len(balances), Discrepancies: []models.LedgerBalance{}}
+	for _, b := range balances {
+		// Остаток хранится с четырьмя знаками, точнее сравнивать нет смысла
+		b.Difference = math.Round((b.Stock-b.LedgerSum)*10000) / 10000
+		if b.Difference != 0 {
+			check.Discrepancies = append(check.Discrepancies, b)
+		}
+	}
+	check.Consistent = len(check.Discrepancies) == 0
+
+	if !check.Consistent {
+		s.Log.Warn("Inventory ledger does not match stock", "discrepancies", len(check.Discrepancies))
+	}
+	return check, nil
+}
@@ -0,0 +1,90 @@
+package svc
+
+import (
+	"fmt"
+	"strings"
+
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetLocations() ([]models.Location, error) {
+	locations, err := s.Repo.LocationRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve locations", "error", err.Error())
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (s *svc) GetLocation(id int) (models.Location, error) {
+	location, err := s.Repo.LocationRepo.GetByID(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve location", "id", id, "error", err.Error())
+		return models.Location{}, err
+	}
+	return location, nil
+}
+
+func (s *svc) CreateLocation(location models.Location) (*models.Location, error) {
+	location.Name = strings.TrimSpace(location.Name)
+	if location.Name == "" {
+		return nil, fmt.Errorf("location name is required")
+	}
+
+	created, err := s.Repo.LocationRepo.Create(location)
+	if err != nil {
+		s.Log.Error("Failed to create location", "name", location.Name, "error", err.Error())
+		return nil, err
+	}
+	s.Log.Info("Location created", "id", created.ID, "name", created.Name)
+	return created, nil
+}
+
+// GetLocationStock возвращает остатки локации и товар, который едет в неё.
+func (s *svc) GetLocationStock(id int) ([]models.LocationStock, error) {
+	if _, err := s.GetLocation(id); err != nil {
+		return nil, err
+	}
+
+	stock, err := s.Repo.LocationRepo.GetStock(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve location stock", "id", id, "error", err.Error())
+		return nil, err
+	}
+	return stock, nil
+}
+
+// checkLocation подставляет основную локацию вместо 0 и проверяет, что локация есть.
+func (s *svc) checkLocation(id *int) error {
+	if *id == 0 {
+		*id = models.DefaultLocationID
+	}
+	if _, err := s.Repo.LocationRepo.GetByID(*id); err != nil {
+		return err
+	}
+	return nil
+}
+
+// locationInventoryMap — склад, где Stock — доступный остаток в локации: заказ
+// кафе может взять только то, что лежит в нём.
+func (s *svc) locationInventoryMap(locationID int) (map[int]models.InventoryItem, error) {
+	inventoryMap, err := s.inventoryMap()
+	if err != nil {
+		return nil, err
+	}
+
+	stock, err := s.Repo.LocationRepo.GetStock(locationID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve location stock", "id", locationID, "error", err.Error())
+		return nil, err
+	}
+	available := make(map[int]float64)
+	for _, item := range stock {
+		available[item.IngredientID] = item.AvailableStock
+	}
+	for id, item := range inventoryMap {
+		item.Stock = available[id]
+		inventoryMap[id] = item
+	}
+	return inventoryMap, nil
+}
@@ -0,0 +1,37 @@
+package svc
+
+import (
+	"fmt"
+
+	"frappuccino/internal/models"
+)
+
+// GetInventoryLots возвращает партии ингредиента; locationID = 0 — во всех локациях.
+func (s *svc) GetInventoryLots(ingredientID, locationID int, all bool) ([]models.InventoryLot, error) {
+	if _, err := s.Repo.InventoryRepo.GetInventoryId(ingredientID); err != nil {
+		s.Log.Error("Failed to fetch inventory item by ID", "id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+
+	lots, err := s.Repo.LotRepo.GetLots(ingredientID, locationID, all)
+	if err != nil {
+		s.Log.Error("Failed to retrieve lots", "id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+	return lots, nil
+}
+
+// GetExpiringLots возвращает партии с остатком, срок годности которых истекает
+// в ближайшие days дней, вместе с уже просроченными; locationID = 0 — во всех локациях.
+func (s *svc) GetExpiringLots(days, locationID int) ([]models.InventoryLot, error) {
+	if days < 0 || days > 365 {
+		return nil, fmt.Errorf("days must be between 0 and 365, got: %d", days)
+	}
+
+	lots, err := s.Repo.LotRepo.GetExpiring(days, locationID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve expiring lots", "error", err.Error())
+		return nil, err
+	}
+	return lots, nil
+}
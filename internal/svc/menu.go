@@ -1,54 +1,40 @@
 package svc
 
 import (
-	"strings"
+	"errors"
+	"fmt"
+	"time"
 
 	"frappuccino/helper"
 	"frappuccino/internal/models"
 	"frappuccino/pkg/cerrors"
 )
 
+// CreateMenuItem сразу добавляет позицию в живое меню, минуя черновик, и
+// сохраняет меню как новую версию. Проверки — те же, что у черновика.
 func (s *svc) CreateMenuItem(item models.MenuItem) (*models.MenuItem, error) {
 	s.Log.Info("Creating new menu item", "name", item.Name)
-	if err := helper.IsValidName(item.Name); err != nil {
-		s.Log.Error("Invalid menu item name", "error", err.Error())
+	change := models.MenuDraftChange{Action: models.DraftActionCreate, Item: &item}
+	if err := s.checkDraftChange(&change); err != nil {
+		s.Log.Error("Invalid menu item", "name", item.Name, "error", err.Error())
 		return nil, err
 	}
 
-	dataInvent, err := s.Repo.InventoryRepo.GetInventory()
+	id, version, err := s.Repo.MenuRepo.ApplyMenuChange(change, fmt.Sprintf("POST /menu: create %q", item.Name))
 	if err != nil {
-		s.Log.Error("Failed to get existing inventory items", "error", err.Error())
-		return nil, err
-	}
-	s.Log.Info("Inventory loaded", "count", len(dataInvent), "items", dataInvent) // Логируем инвентарь
-
-	if len(item.Ingredients) > 0 {
-		if err := helper.CheckerForMenuItems(item, dataInvent, item.Ingredients); err != nil {
-			s.Log.Error("Invalid menu item ingredients", "error", err.Error())
-			return nil, err
-		}
-	}
-
-	createdItem, err := s.Repo.MenuRepo.CreateMenuItem(item)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key value") {
+		if errors.Is(err, cerrors.ErrExist) {
 			s.Log.Error("Menu item already exists", "name", item.Name)
 			return nil, cerrors.ErrExist
 		}
 		s.Log.Error("Failed to create menu item in repository", "error", err.Error())
 		return nil, err
 	}
+	item.ID = id
 
-	for _, ing := range item.Ingredients {
-		err = s.Repo.MenuRepo.AddIngredientToMenuItem(createdItem.ID, ing)
-		if err != nil {
-			s.Log.Error("Failed to add ingredient", "menu_item_id", createdItem.ID, "error", err.Error())
-			return nil, err
-		}
-	}
+	s.syncStockAvailability()
 
-	s.Log.Info("Successfully created menu item", "id", createdItem.ID)
-	return createdItem, nil
+	s.Log.Info("Successfully created menu item", "id", id, "version", version.ID)
+	return &item, nil
 }
 
 func (s *svc) GetAllMenuItems() ([]models.MenuItem, error) {
@@ -64,6 +50,100 @@ func (s *svc) GetAllMenuItems() ([]models.MenuItem, error) {
 	return items, nil
 }
 
+// ListMenu возвращает меню с текущими ценами. Без filter.All в ответ попадают
+// только позиции, которые можно заказать в момент filter.At.
+func (s *svc) ListMenu(filter models.MenuFilter) ([]models.MenuItem, error) {
+	if filter.At.IsZero() {
+		filter.At = time.Now()
+	}
+
+	items, err := s.GetAllMenuItems()
+	if err != nil {
+		return nil, err
+	}
+
+	var categories map[int]bool
+	if filter.Category != "" {
+		if categories, err = s.categoryWithDescendants(filter.Category); err != nil {
+			s.Log.Error("Invalid category filter", "category", filter.Category, "error", err.Error())
+			return nil, err
+		}
+	}
+
+	windows, err := s.Repo.MenuRepo.GetAvailabilityWindows()
+	if err != nil {
+		s.Log.Error("Failed to retrieve availability windows", "error", err.Error())
+		return nil, err
+	}
+
+	rules, err := s.Repo.PriceRuleRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve price rules", "error", err.Error())
+		return nil, err
+	}
+
+	if filter.LocationID != 0 {
+		if err := s.checkLocation(&filter.LocationID); err != nil {
+			s.Log.Error("Invalid menu location", "location_id", filter.LocationID, "error", err.Error())
+			return nil, err
+		}
+	}
+	if err := s.applyStock(items, filter.LocationID); err != nil {
+		return nil, err
+	}
+
+	components, err := s.Repo.MenuRepo.GetBundleComponents()
+	if err != nil {
+		s.Log.Error("Failed to retrieve bundle components", "error", err.Error())
+		return nil, err
+	}
+
+	images, err := s.Repo.MenuRepo.GetImages()
+	if err != nil {
+		s.Log.Error("Failed to retrieve images", "error", err.Error())
+		return nil, err
+	}
+
+	availableNow := make(map[int]bool)
+	for i := range items {
+		items[i].Components = components[items[i].ID]
+		items[i].Images = s.withImageURLs(images[items[i].ID])
+		items[i].AvailabilityWindows = windows[items[i].ID]
+		items[i].AvailableNow = helper.IsAvailableAt(items[i], filter.At)
+		items[i].CurrentPrice, _ = helper.PriceAt(items[i], rules, filter.At)
+		availableNow[items[i].ID] = items[i].AvailableNow
+	}
+
+	result := make([]models.MenuItem, 0, len(items))
+	for _, item := range items {
+		// Набор можно заказать, только если сейчас доступны все его компоненты
+		for _, c := range item.Components {
+			if !availableNow[c.MenuItemID] {
+				item.AvailableNow = false
+			}
+		}
+		if !filter.All && !item.AvailableNow {
+			continue
+		}
+		if categories != nil && !inCategories(item, categories) {
+			continue
+		}
+		if !helper.MatchesNutrition(item.Nutrition, filter) {
+			continue
+		}
+		result = append(result, item)
+	}
+
+	if len(filter.Languages) > 0 {
+		if err := s.localize(result, filter.Languages); err != nil {
+			return nil, err
+		}
+	}
+
+	s.Log.Info("Menu listed", "count", len(result), "all", filter.All, "category", filter.Category)
+	return result, nil
+}
+
 func (s *svc) GetMenuItemByID(id int) (*models.MenuItem, error) {
 	s.Log.Info("Retrieving menu item by ID", "id", id)
 
@@ -73,54 +153,119 @@ func (s *svc) GetMenuItemByID(id int) (*models.MenuItem, error) {
 		return nil, err // Репозиторий уже возвращает cerrors.ErrMenuItemNotFound
 	}
 
-	s.Log.Info("Successfully retrieved menu item", "id", id)
-	return item, nil
-}
+	item.Components, err = s.Repo.MenuRepo.GetBundleComponentsByID(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve bundle components", "id", id, "error", err.Error())
+		return nil, err
+	}
 
-func (s *svc) UpdateMenuItem(id int, item models.MenuItem) (*models.MenuItem, error) {
-	s.Log.Info("Updating menu item", "id", id)
+	item.AvailabilityWindows, err = s.Repo.MenuRepo.GetAvailabilityWindowsByMenuItemID(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve availability windows", "id", id, "error", err.Error())
+		return nil, err
+	}
 
-	if err := helper.IsValidName(item.Name); err != nil {
-		s.Log.Error("Invalid menu item name", "error", err.Error())
+	images, err := s.Repo.MenuRepo.GetImagesByMenuItemID(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve images", "id", id, "error", err.Error())
 		return nil, err
 	}
+	item.Images = s.withImageURLs(images)
 
-	dataInvent, err := s.Repo.InventoryRepo.GetInventory()
+	item.Translations, err = s.Repo.MenuRepo.GetTranslationsByMenuItemID(id)
 	if err != nil {
-		s.Log.Error("Failed to get existing inventory items", "error", err.Error())
+		s.Log.Error("Failed to retrieve translations", "id", id, "error", err.Error())
 		return nil, err
 	}
 
-	ingredients, err := s.Repo.MenuRepo.GetIngredientsByMenuItemID(item.ID)
+	rules, err := s.Repo.PriceRuleRepo.GetAll()
 	if err != nil {
-		s.Log.Error("Failed to get ingredients", "menu_item_id", item.ID, "error", err.Error())
+		s.Log.Error("Failed to retrieve price rules", "error", err.Error())
 		return nil, err
 	}
 
-	if err := helper.CheckerForMenuItems(item, dataInvent, ingredients); err != nil {
-		s.Log.Error("Invalid menu item ingredients", "error", err.Error())
+	stock := []models.MenuItem{*item}
+	if err := s.applyStock(stock, 0); err != nil {
 		return nil, err
 	}
+	item = &stock[0]
+
+	now := time.Now()
+	item.AvailableNow = helper.IsAvailableAt(*item, now)
+	item.CurrentPrice, _ = helper.PriceAt(*item, rules, now)
 
-	updatedItem, err := s.Repo.MenuRepo.UpdateMenuItem(id, item)
+	if item.Type == models.MenuItemBundle && item.AvailableNow {
+		// Доступность набора зависит от компонентов, проще взять её из общего списка
+		menu, err := s.ListMenu(models.MenuFilter{All: true, At: now})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range menu {
+			if m.ID == id {
+				item.AvailableNow = m.AvailableNow
+			}
+		}
+	}
+
+	s.Log.Info("Successfully retrieved menu item", "id", id)
+	return item, nil
+}
+
+// UpdateMenuItem сразу меняет позицию в живом меню и сохраняет меню как новую
+// версию. Рецептура и состав набора заменяются, только если переданы.
+func (s *svc) UpdateMenuItem(id int, item models.MenuItem) (*models.MenuItem, error) {
+	s.Log.Info("Updating menu item", "id", id)
+	change := models.MenuDraftChange{Action: models.DraftActionUpdate, MenuItemID: &id, Item: &item}
+	if err := s.checkDraftChange(&change); err != nil {
+		s.Log.Error("Invalid menu item", "id", id, "error", err.Error())
+		return nil, err
+	}
+
+	_, version, err := s.Repo.MenuRepo.ApplyMenuChange(change, fmt.Sprintf("PUT /menu/%d: update %q", id, item.Name))
 	if err != nil {
 		s.Log.Error("Failed to update menu item", "id", id, "error", err.Error())
 		return nil, err
 	}
 
-	s.Log.Info("Successfully updated menu item", "id", id)
-	return updatedItem, nil
+	s.syncStockAvailability()
+
+	s.Log.Info("Successfully updated menu item", "id", id, "version", version.ID)
+	return &item, nil
 }
 
+// DeleteMenuItem удаляет позицию из живого меню и сохраняет меню как новую версию.
 func (s *svc) DeleteMenuItem(id int) error {
 	s.Log.Info("Deleting menu item", "id", id)
 
-	err := s.Repo.MenuRepo.DeleteMenuItem(id)
+	change := models.MenuDraftChange{Action: models.DraftActionDelete, MenuItemID: &id}
+	if err := s.checkDraftChange(&change); err != nil {
+		s.Log.Error("Failed to delete menu item", "id", id, "error", err.Error())
+		return err // cerrors.ErrMenuItemNotFound, если позиции нет
+	}
+
+	images := s.imageSnapshot()
+	_, version, err := s.Repo.MenuRepo.ApplyMenuChange(change, fmt.Sprintf("DELETE /menu/%d", id))
 	if err != nil {
 		s.Log.Error("Failed to delete menu item", "id", id, "error", err.Error())
-		return err // Репозиторий возвращает cerrors.ErrMenuItemNotFound
+		return err
 	}
 
-	s.Log.Info("Successfully deleted menu item", "id", id)
+	// Записи об изображениях удалены каскадно, осталось убрать файлы
+	s.removeDroppedImages(images)
+
+	s.Log.Info("Successfully deleted menu item", "id", id, "version", version.ID)
+	return nil
+}
+
+func (s *svc) checkBundle(item models.MenuItem) error {
+	menu, err := s.Repo.MenuRepo.GetAllMenuItems()
+	if err != nil {
+		s.Log.Error("Failed to retrieve menu items", "error", err.Error())
+		return err
+	}
+	if err := helper.CheckBundle(item, menu); err != nil {
+		s.Log.Error("Invalid bundle", "name", item.Name, "error", err.Error())
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,241 @@
+package svc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+// ExportMenu выгружает всё меню с категориями, аллергенами, ценами и рецептурами
+// в формате, который принимает ImportMenu.
+func (s *svc) ExportMenu() ([]models.MenuExportItem, error) {
+	items, err := s.liveMenuSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.MenuExportItem, 0, len(items))
+	for _, item := range items {
+		available, price := item.Available, item.Price
+		description, size := item.Description, item.Size
+		exported := models.MenuExportItem{
+			ID:          item.ID,
+			Type:        item.Type,
+			Name:        item.Name,
+			Description: &description,
+			Categories:  item.Categories,
+			Allergens:   item.Allergens,
+			Price:       &price,
+			Available:   &available,
+			Size:        &size,
+			Ingredients: item.Ingredients,
+			Components:  item.Components,
+		}
+		if exported.Categories == nil {
+			exported.Categories = []string{}
+		}
+		if exported.Allergens == nil {
+			exported.Allergens = []string{}
+		}
+		if exported.Ingredients == nil {
+			exported.Ingredients = []models.MenuItemIngredient{}
+		}
+		result = append(result, exported)
+	}
+
+	s.Log.Info("Menu exported", "count", len(result))
+	return result, nil
+}
+
+// ImportMenu разбирает выгрузку в формате json или csv и применяет её одной
+// транзакцией. Строки проверяются так же, как POST /menu и PUT /menu/{id};
+// если хоть одна строка отклонена или задан dryRun, меню не меняется и
+// возвращается только отчёт.
+func (s *svc) ImportMenu(data io.Reader, format string, dryRun bool) (*models.MenuImportResult, error) {
+	var records []models.MenuExportItem
+	var rowErrors []error
+	switch format {
+	case "json":
+		if err := json.NewDecoder(data).Decode(&records); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		rowErrors = make([]error, len(records))
+	case "csv":
+		var err error
+		if records, rowErrors, err = helper.ReadMenuCSV(data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("import format must be json or csv, got: %q", format)
+	}
+
+	existing, err := s.liveMenuSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]models.MenuItem, len(existing))
+	byName := make(map[string]models.MenuItem, len(existing))
+	for _, item := range existing {
+		byID[item.ID] = item
+		byName[strings.ToLower(item.Name)] = item
+	}
+
+	result := &models.MenuImportResult{
+		DryRun:    dryRun,
+		Created:   []models.MenuImportRow{},
+		Updated:   []models.MenuImportRow{},
+		Unchanged: []models.MenuImportRow{},
+		Rejected:  []models.MenuImportRow{},
+	}
+	var changes []models.MenuDraftChange
+	seenNames := make(map[string]int)
+	seenIDs := make(map[int]int)
+
+	for i, rec := range records {
+		row := models.MenuImportRow{Row: i + 1, MenuItemID: rec.ID, Name: rec.Name}
+		change, err := s.importChange(rec, rowErrors[i], byID, byName)
+		if err == nil {
+			key := strings.ToLower(strings.TrimSpace(rec.Name))
+			if prev, dup := seenNames[key]; dup {
+				err = fmt.Errorf("duplicate name %q, already used in row %d", rec.Name, prev)
+			} else if change.MenuItemID != nil && seenIDs[*change.MenuItemID] != 0 {
+				err = fmt.Errorf("menu item %d is already updated by row %d", *change.MenuItemID, seenIDs[*change.MenuItemID])
+			}
+			seenNames[key] = row.Row
+			if change.MenuItemID != nil && err == nil {
+				seenIDs[*change.MenuItemID] = row.Row
+			}
+		}
+		if err != nil {
+			row.Action, row.Error = models.ImportActionReject, err.Error()
+			result.Rejected = append(result.Rejected, row)
+			continue
+		}
+
+		if change.Action == models.DraftActionCreate {
+			row.Action = models.ImportActionCreate
+			result.Created = append(result.Created, row)
+			changes = append(changes, change)
+			continue
+		}
+
+		old := byID[*change.MenuItemID]
+		merged := *change.Item
+		merged.ID = old.ID
+		if merged.Ingredients == nil {
+			merged.Ingredients = old.Ingredients
+		}
+		if merged.Components == nil {
+			merged.Components = old.Components
+		}
+		row.MenuItemID = old.ID
+		row.Changes = helper.DiffMenuItem(old, merged)
+		if len(row.Changes) == 0 {
+			row.Action, row.Changes = models.ImportActionUnchanged, nil
+			result.Unchanged = append(result.Unchanged, row)
+			continue
+		}
+		row.Action = models.ImportActionUpdate
+		result.Updated = append(result.Updated, row)
+		changes = append(changes, change)
+	}
+
+	s.Log.Info("Menu import checked", "rows", len(records), "created", len(result.Created),
+		"updated", len(result.Updated), "unchanged", len(result.Unchanged), "rejected", len(result.Rejected), "dry_run", dryRun)
+
+	if dryRun || len(result.Rejected) > 0 || len(changes) == 0 {
+		return result, nil
+	}
+
+	images := s.imageSnapshot()
+	result.Version, err = s.Repo.MenuRepo.ImportMenu(changes, fmt.Sprintf("import of %d rows", len(records)))
+	if err != nil {
+		s.Log.Error("Failed to import menu", "error", err.Error())
+		return nil, err
+	}
+	result.Applied = true
+	s.removeDroppedImages(images)
+
+	s.syncStockAvailability()
+
+	s.Log.Info("Menu imported", "version", result.Version.ID, "changes", len(changes))
+	return result, nil
+}
+
+// importChange превращает строку импорта в изменение меню: с id — обновление
+// этой позиции, без id — обновление позиции с тем же именем или создание новой.
+func (s *svc) importChange(rec models.MenuExportItem, rowErr error, byID map[int]models.MenuItem, byName map[string]models.MenuItem) (models.MenuDraftChange, error) {
+	var change models.MenuDraftChange
+	if rowErr != nil {
+		return change, rowErr
+	}
+	if rec.Price != nil && *rec.Price < 0 {
+		return change, fmt.Errorf("price must not be negative, got: %v", *rec.Price)
+	}
+
+	item := &models.MenuItem{
+		Type:        rec.Type,
+		Name:        strings.TrimSpace(rec.Name),
+		Categories:  rec.Categories,
+		Allergens:   rec.Allergens,
+		Available:   true,
+		Ingredients: rec.Ingredients,
+		Components:  rec.Components,
+	}
+
+	target, found := byName[strings.ToLower(item.Name)]
+	if rec.ID != 0 {
+		byIDItem, exists := byID[rec.ID]
+		if !exists {
+			return change, fmt.Errorf("menu item with ID %d not found", rec.ID)
+		}
+		if found && target.ID != rec.ID {
+			return change, fmt.Errorf("name %q is already used by menu item %d", item.Name, target.ID)
+		}
+		target, found = byIDItem, true
+	}
+
+	change.Item = item
+	if found {
+		id := target.ID
+		change.Action, change.MenuItemID = models.DraftActionUpdate, &id
+		item.Available, item.Price = target.Available, target.Price
+		item.Description, item.Size = target.Description, target.Size
+		if item.Type == "" {
+			item.Type = target.Type
+		}
+		// Пропущенная колонка или поле не должны стирать значения позиции
+		if rec.Categories == nil {
+			item.CategoryIDs = target.CategoryIDs
+		}
+		if rec.Allergens == nil {
+			item.Allergens = target.Allergens
+		}
+	} else {
+		if rec.Price == nil {
+			return change, fmt.Errorf("please provide a price for the new menu item")
+		}
+		change.Action = models.DraftActionCreate
+	}
+	if rec.Price != nil {
+		item.Price = *rec.Price
+	}
+	if rec.Description != nil {
+		item.Description = *rec.Description
+	}
+	if rec.Size != nil {
+		item.Size = *rec.Size
+	}
+	if rec.Available != nil {
+		item.Available = *rec.Available
+	}
+
+	if err := s.checkDraftChange(&change); err != nil {
+		return change, err
+	}
+	return change, nil
+}
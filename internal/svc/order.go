@@ -2,6 +2,7 @@ package svc
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"frappuccino/helper"
@@ -13,8 +14,14 @@ func (s *svc) OrderCreate(data models.Order) error {
 		s.Log.Error("Invalid customer ID", "customer_id", data.CustomerID)
 		return fmt.Errorf("invalid customer ID")
 	}
+	if err := s.checkLocation(&data.LocationID); err != nil {
+		s.Log.Error("Invalid order location", "location_id", data.LocationID, "error", err.Error())
+		return err
+	}
 
-	dataMenu, err := s.GetAllMenuItems()
+	now := time.Now()
+	// Доступность считаем по складу кафе заказа: списываться будет именно он
+	dataMenu, err := s.ListMenu(models.MenuFilter{All: true, At: now, LocationID: data.LocationID})
 	if err != nil {
 		s.Log.Error("Failed to retrieve menu", "error", err.Error())
 		return err
@@ -25,14 +32,85 @@ func (s *svc) OrderCreate(data models.Order) error {
 		return err
 	}
 
+	if err := s.priceOrder(&data, dataMenu, nil); err != nil {
+		s.Log.Error("Order pricing failed", "customer_id", data.CustomerID, "error", err.Error())
+		return err
+	}
+
+	usage, err := s.orderUsage(&data)
+	if err != nil {
+		return err
+	}
+
 	data.Status = "open"
-	data.CreatedAt = time.Now()
-	if err := s.Repo.OrderRepo.CreateOrder(data); err != nil {
+	data.CreatedAt = now
+	if err := s.Repo.OrderRepo.CreateOrder(data, usage); err != nil {
 		s.Log.Error("Failed to create order", "customer_id", data.CustomerID, "error", err.Error())
 		return err
 	}
 
-	s.Log.Info("Successfully created order", "id", data.ID)
+	s.syncStockAvailability()
+
+	changes := make(map[int]float64)
+	for _, u := range usage {
+		changes[u.IngredientID] -= u.Quantity
+	}
+	s.notifyLowStock(changes)
+
+	s.Log.Info("Successfully created order", "id", data.ID, "total", data.TotalAmount)
+	return nil
+}
+
+// priceOrder проверяет, что все позиции доступны сейчас, и проставляет цены
+// по действующим правилам (happy hour и т.п.) вместе с итоговой суммой заказа.
+// stored — строки заказа до редактирования: строка той же позиции с количеством
+// не больше прежнего сохраняет свою цену и заново не проверяется.
+func (s *svc) priceOrder(data *models.Order, menu []models.MenuItem, stored []models.OrderItem) error {
+	menuMap := make(map[int]models.MenuItem)
+	for _, item := range menu {
+		menuMap[item.ID] = item
+	}
+
+	used := make([]bool, len(stored))
+	kept := make([]bool, len(data.Items))
+	for i, orderItem := range data.Items {
+		for j, old := range stored {
+			if !used[j] && old.MenuItemID == orderItem.MenuItemID && orderItem.Quantity <= old.Quantity {
+				used[j], kept[i] = true, true
+				data.Items[i].Price = old.Price
+				break
+			}
+		}
+	}
+
+	requested := make(map[int]int)
+	for i, orderItem := range data.Items {
+		if !kept[i] {
+			requested[orderItem.MenuItemID] += orderItem.Quantity
+		}
+	}
+
+	total := 0.0
+	for i, orderItem := range data.Items {
+		if kept[i] {
+			total += orderItem.Price * float64(orderItem.Quantity)
+			continue
+		}
+		menuItem := menuMap[orderItem.MenuItemID]
+		if menuItem.SoldOut {
+			return fmt.Errorf("menu item %s (ID %d) is sold out", menuItem.Name, menuItem.ID)
+		}
+		if !menuItem.AvailableNow {
+			return fmt.Errorf("menu item %s (ID %d) is not available at this time", menuItem.Name, menuItem.ID)
+		}
+		if p := menuItem.PortionsAvailable; p != nil && *p < requested[menuItem.ID] {
+			return fmt.Errorf("not enough stock to make %d x %s (ID %d): only %d portions available",
+				requested[menuItem.ID], menuItem.Name, menuItem.ID, *p)
+		}
+		data.Items[i].Price = menuItem.CurrentPrice
+		total += menuItem.CurrentPrice * float64(orderItem.Quantity)
+	}
+	data.TotalAmount = math.Round(total*100) / 100
 	return nil
 }
 
@@ -64,7 +142,13 @@ func (s *svc) Update(id int, data models.Order) error {
 		return fmt.Errorf("invalid customer ID")
 	}
 
-	dataMenu, err := s.GetAllMenuItems()
+	existing, err := s.Repo.OrderRepo.GetOrderByID(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve order by ID", "id", id, "error", err.Error())
+		return err
+	}
+
+	dataMenu, err := s.ListMenu(models.MenuFilter{All: true, LocationID: existing.LocationID})
 	if err != nil {
 		s.Log.Error("Failed to retrieve menu", "error", err.Error())
 		return err
@@ -75,6 +159,11 @@ func (s *svc) Update(id int, data models.Order) error {
 		return err
 	}
 
+	if err := s.priceOrder(&data, dataMenu, existing.Items); err != nil {
+		s.Log.Error("Order pricing failed", "id", id, "error", err.Error())
+		return err
+	}
+
 	if err := s.Repo.OrderRepo.UpdateOrder(id, data); err != nil {
 		s.Log.Error("Failed to update order", "id", id, "error", err.Error())
 		return err
@@ -106,57 +195,7 @@ func (s *svc) CloseOrder(id int) error {
 		return fmt.Errorf("operation not allowed: status is closed")
 	}
 
-	menuAll, err := s.Repo.MenuRepo.GetAllMenuItems()
-	if err != nil {
-		s.Log.Error("Failed to retrieve menu items", "error", err.Error())
-		return err
-	}
-
-	menuMap := make(map[int]models.MenuItem)
-	for _, item := range menuAll {
-		menuMap[item.ID] = item
-	}
-
-	inventoryAll, err := s.Repo.InventoryRepo.GetInventory()
-	if err != nil {
-		s.Log.Error("Failed to retrieve inventory", "error", err.Error())
-		return err
-	}
-
-	ingredientMap := make(map[int]models.InventoryItem)
-	for _, item := range inventoryAll {
-		ingredientMap[item.ID] = item
-	}
-
-	for _, orderItem := range order.Items {
-		if menuItem, exists := menuMap[orderItem.MenuItemID]; exists {
-			ingredients, err := s.Repo.MenuRepo.GetIngredientsByMenuItemID(menuItem.ID)
-			if err != nil {
-				s.Log.Error("Failed to get ingredients", "menu_item_id", menuItem.ID, "error", err.Error())
-				return err
-			}
-
-			for _, ingredient := range ingredients {
-				requiredQty := ingredient.Quantity * float64(orderItem.Quantity)
-				if invItem, exists := ingredientMap[ingredient.IngredientID]; exists {
-					if requiredQty > invItem.Stock {
-						s.Log.Error("Not enough quantity for ingredient", "ingredient_id", ingredient.IngredientID, "required", requiredQty, "available", invItem.Stock)
-						return fmt.Errorf("not enough quantity for ingredient ID %d: required %v, available %v", ingredient.IngredientID, requiredQty, invItem.Stock)
-					}
-					invItem.Stock -= requiredQty
-					ingredientMap[ingredient.IngredientID] = invItem
-				}
-			}
-		}
-	}
-
-	for ingredientID, item := range ingredientMap {
-		if err := s.Repo.InventoryRepo.PutInventory(ingredientID, item); err != nil {
-			s.Log.Error("Failed to update inventory", "ingredient_id", ingredientID, "error", err.Error())
-			return err
-		}
-	}
-
+	// Ингредиенты (с учётом замен) списаны при создании заказа, повторно не списываем
 	if err := s.Repo.OrderRepo.CloseOrder(id); err != nil {
 		s.Log.Error("Failed to close order", "id", id, "error", err.Error())
 		return err
@@ -0,0 +1,102 @@
+package svc
+
+import (
+	"fmt"
+	"time"
+
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetPriceHistory(menuItemID int) ([]models.PriceHistory, error) {
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	history, err := s.Repo.MenuRepo.GetPriceHistory(menuItemID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve price history", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Successfully retrieved price history", "id", menuItemID, "count", len(history))
+	return history, nil
+}
+
+func (s *svc) SchedulePriceChange(menuItemID int, change models.ScheduledPriceChange) (*models.ScheduledPriceChange, error) {
+	if change.NewPrice < 0 {
+		s.Log.Error("Invalid scheduled price", "id", menuItemID, "price", change.NewPrice)
+		return nil, fmt.Errorf("new price cannot be negative, got: %f", change.NewPrice)
+	}
+	if change.EffectiveAt.IsZero() {
+		s.Log.Error("Missing effective date for price change", "id", menuItemID)
+		return nil, fmt.Errorf("please provide effective_at for the price change")
+	}
+	if !change.EffectiveAt.After(time.Now()) {
+		s.Log.Error("Effective date is in the past", "id", menuItemID, "effective_at", change.EffectiveAt)
+		return nil, fmt.Errorf("effective_at must be in the future, use PUT /menu/%d to change the price now", menuItemID)
+	}
+
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	change.MenuItemID = menuItemID
+	created, err := s.Repo.MenuRepo.CreateScheduledPriceChange(change)
+	if err != nil {
+		s.Log.Error("Failed to schedule price change", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Price change scheduled", "id", menuItemID, "new_price", created.NewPrice, "effective_at", created.EffectiveAt)
+	return created, nil
+}
+
+func (s *svc) GetScheduledPriceChanges(menuItemID int) ([]models.ScheduledPriceChange, error) {
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	changes, err := s.Repo.MenuRepo.GetScheduledPriceChanges(menuItemID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve scheduled price changes", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (s *svc) CancelScheduledPriceChange(menuItemID, changeID int) error {
+	if err := s.Repo.MenuRepo.DeleteScheduledPriceChange(menuItemID, changeID); err != nil {
+		s.Log.Error("Failed to cancel scheduled price change", "id", menuItemID, "change_id", changeID, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Scheduled price change cancelled", "id", menuItemID, "change_id", changeID)
+	return nil
+}
+
+func (s *svc) ApplyScheduledPriceChanges() (int, error) {
+	applied, err := s.Repo.MenuRepo.ApplyDuePriceChanges(time.Now())
+	if err != nil {
+		s.Log.Error("Failed to apply scheduled price changes", "error", err.Error())
+		return 0, err
+	}
+
+	for _, c := range applied {
+		s.Log.Info("Scheduled price change applied", "id", c.MenuItemID, "change_id", c.ID, "new_price", c.NewPrice)
+	}
+	return len(applied), nil
+}
+
+// RunPriceScheduler блокирует и раз в interval применяет наступившие изменения цен.
+func (s *svc) RunPriceScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.ApplyScheduledPriceChanges()
+		<-ticker.C
+	}
+}
@@ -0,0 +1,209 @@
+package svc
+
+import (
+	"fmt"
+	"time"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetPurchaseOrders(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, error) {
+	switch filter.Status {
+	case "", models.PurchaseOrderDraft, models.PurchaseOrderSent,
+		models.PurchaseOrderPartiallyReceived, models.PurchaseOrderReceived:
+	default:
+		return nil, fmt.Errorf("invalid status: %s, expected draft, sent, partially_received or received", filter.Status)
+	}
+
+	orders, err := s.Repo.SupplierRepo.GetPurchaseOrders(filter)
+	if err != nil {
+		s.Log.Error("Failed to retrieve purchase orders", "error", err.Error())
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (s *svc) GetPurchaseOrder(id int) (models.PurchaseOrder, error) {
+	po, err := s.Repo.SupplierRepo.GetPurchaseOrder(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve purchase order", "id", id, "error", err.Error())
+		return models.PurchaseOrder{}, err
+	}
+	return po, nil
+}
+
+// CreatePurchaseOrder создаёт черновик заказа поставщику.
+func (s *svc) CreatePurchaseOrder(po models.PurchaseOrder) (*models.PurchaseOrder, error) {
+	if po.ID != 0 {
+		return nil, fmt.Errorf("purchase order ID should not be set when adding a new purchase order")
+	}
+	if err := s.checkPurchaseOrder(&po); err != nil {
+		s.Log.Error("Invalid purchase order", "supplier_id", po.SupplierID, "error", err.Error())
+		return nil, err
+	}
+
+	created, err := s.Repo.SupplierRepo.CreatePurchaseOrder(po)
+	if err != nil {
+		s.Log.Error("Failed to create purchase order", "supplier_id", po.SupplierID, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Purchase order created", "id", created.ID, "supplier_id", created.SupplierID)
+	return s.purchaseOrder(created.ID)
+}
+
+func (s *svc) UpdatePurchaseOrder(id int, po models.PurchaseOrder) error {
+	if err := s.checkPurchaseOrder(&po); err != nil {
+		s.Log.Error("Invalid purchase order", "id", id, "error", err.Error())
+		return err
+	}
+
+	if err := s.Repo.SupplierRepo.UpdatePurchaseOrder(id, po); err != nil {
+		s.Log.Error("Failed to update purchase order", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Purchase order updated", "id", id)
+	return nil
+}
+
+func (s *svc) DeletePurchaseOrder(id int) error {
+	if err := s.Repo.SupplierRepo.DeletePurchaseOrder(id); err != nil {
+		s.Log.Error("Failed to delete purchase order", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Purchase order deleted", "id", id)
+	return nil
+}
+
+// SendPurchaseOrder отмечает черновик отправленным; ожидаемая дата поставки —
+// сегодня плюс срок поставки поставщика.
+func (s *svc) SendPurchaseOrder(id int) (*models.PurchaseOrder, error) {
+	po, err := s.Repo.SupplierRepo.GetPurchaseOrder(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve purchase order", "id", id, "error", err.Error())
+		return nil, err
+	}
+	supplier, err := s.Repo.SupplierRepo.GetByID(po.SupplierID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve supplier", "id", po.SupplierID, "error", err.Error())
+		return nil, err
+	}
+
+	expectedAt := time.Now().AddDate(0, 0, supplier.LeadTimeDays)
+	if err := s.Repo.SupplierRepo.SendPurchaseOrder(id, expectedAt); err != nil {
+		s.Log.Error("Failed to send purchase order", "id", id, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Purchase order sent", "id", id, "supplier", supplier.Name, "expected_at", expectedAt.Format(time.DateOnly))
+	return s.purchaseOrder(id)
+}
+
+// ReceivePurchaseOrder приходует поставку по заказу. Без строк в receipt
+// принимается весь недополученный остаток.
+func (s *svc) ReceivePurchaseOrder(id int, receipt models.PurchaseReceipt) (*models.PurchaseOrder, error) {
+	po, err := s.Repo.SupplierRepo.GetPurchaseOrder(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve purchase order", "id", id, "error", err.Error())
+		return nil, err
+	}
+
+	var lines []models.ReceiptLine
+	if len(receipt.Lines) == 0 {
+		for _, line := range po.Lines {
+			if outstanding := line.Quantity - line.ReceivedQuantity; outstanding > 0 {
+				lines = append(lines, models.ReceiptLine{LineID: line.ID, IngredientID: line.IngredientID, Quantity: outstanding})
+			}
+		}
+	}
+	for _, r := range receipt.Lines {
+		if r.Quantity <= 0 {
+			return nil, fmt.Errorf("received quantity must be greater than 0, got: %v", r.Quantity)
+		}
+		if err := helper.CheckExpiryDate(r.ExpiresAt); err != nil {
+			return nil, err
+		}
+		found := false
+		for _, line := range po.Lines {
+			if (r.LineID != 0 && line.ID == r.LineID) || (r.LineID == 0 && line.IngredientID == r.IngredientID) {
+				r.LineID, r.IngredientID = line.ID, line.IngredientID
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("purchase order %d has no line for line_id %d / ingredient_id %d", id, r.LineID, r.IngredientID)
+		}
+		lines = append(lines, r)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("purchase order %d has nothing left to receive", id)
+	}
+
+	transactions, err := s.Repo.SupplierRepo.ReceivePurchaseOrder(id, lines, receipt.Note)
+	if err != nil {
+		s.Log.Error("Failed to receive purchase order", "id", id, "error", err.Error())
+		return nil, err
+	}
+
+	s.syncStockAvailability()
+
+	s.Log.Info("Purchase order received", "id", id, "lines", len(transactions))
+	return s.purchaseOrder(id)
+}
+
+func (s *svc) purchaseOrder(id int) (*models.PurchaseOrder, error) {
+	po, err := s.Repo.SupplierRepo.GetPurchaseOrder(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve purchase order", "id", id, "error", err.Error())
+		return nil, err
+	}
+	return &po, nil
+}
+
+// checkPurchaseOrder проверяет строки заказа: ингредиент есть в прайсе поставщика
+// и встречается один раз. Цена по умолчанию — из прайса.
+func (s *svc) checkPurchaseOrder(po *models.PurchaseOrder) error {
+	supplier, err := s.Repo.SupplierRepo.GetByID(po.SupplierID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkLocation(&po.LocationID); err != nil {
+		return err
+	}
+	if len(po.Lines) == 0 {
+		return fmt.Errorf("purchase order must have at least one line")
+	}
+
+	prices := make(map[int]float64)
+	for _, item := range supplier.Items {
+		prices[item.IngredientID] = item.UnitPrice
+	}
+
+	seen := make(map[int]bool)
+	for i := range po.Lines {
+		line := &po.Lines[i]
+		price, ok := prices[line.IngredientID]
+		if !ok {
+			return fmt.Errorf("supplier %s does not supply ingredient %d", supplier.Name, line.IngredientID)
+		}
+		if seen[line.IngredientID] {
+			return fmt.Errorf("ingredient %d is listed more than once", line.IngredientID)
+		}
+		seen[line.IngredientID] = true
+		if line.Quantity <= 0 {
+			return fmt.Errorf("quantity of ingredient %d must be greater than 0", line.IngredientID)
+		}
+		if line.UnitPrice < 0 {
+			return fmt.Errorf("unit_price of ingredient %d cannot be negative", line.IngredientID)
+		}
+		if line.UnitPrice == 0 {
+			line.UnitPrice = price
+		}
+		line.ReceivedQuantity = 0
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package svc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+// defaultHorizonDays — на сколько дней после поставки заказывать по умолчанию.
+const defaultHorizonDays = 7
+
+// GetReorderSuggestions предлагает, что и сколько дозаказать, по среднему расходу,
+// порогу дозаказа и сроку поставки. Для каждого ингредиента берётся самый дешёвый
+// поставщик (или указанный в фильтре); ингредиенты без поставщика тоже попадают
+// в список, но без supplier_id.
+func (s *svc) GetReorderSuggestions(filter models.ReorderFilter) ([]models.ReorderSuggestion, error) {
+	if filter.HorizonDays == 0 {
+		filter.HorizonDays = defaultHorizonDays
+	}
+	if filter.UsageDays == 0 {
+		filter.UsageDays = usageWindowDays
+	}
+	if filter.HorizonDays < 0 || filter.HorizonDays > 365 {
+		return nil, fmt.Errorf("horizon must be between 1 and 365 days, got: %d", filter.HorizonDays)
+	}
+	if filter.UsageDays < 0 || filter.UsageDays > 365 {
+		return nil, fmt.Errorf("usage window must be between 1 and 365 days, got: %d", filter.UsageDays)
+	}
+
+	inventory, err := s.availableInventory()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.Repo.InventoryRepo.GetDailyUsage(filter.UsageDays)
+	if err != nil {
+		s.Log.Error("Failed to retrieve daily usage", "error", err.Error())
+		return nil, err
+	}
+	onOrder, err := s.Repo.SupplierRepo.GetOnOrder()
+	if err != nil {
+		s.Log.Error("Failed to retrieve quantities on order", "error", err.Error())
+		return nil, err
+	}
+
+	var suppliers []models.Supplier
+	if filter.SupplierID != 0 {
+		supplier, err := s.Repo.SupplierRepo.GetByID(filter.SupplierID)
+		if err != nil {
+			s.Log.Error("Failed to retrieve supplier", "id", filter.SupplierID, "error", err.Error())
+			return nil, err
+		}
+		suppliers = []models.Supplier{supplier}
+	} else {
+		suppliers, err = s.Repo.SupplierRepo.GetAll()
+		if err != nil {
+			s.Log.Error("Failed to retrieve suppliers", "error", err.Error())
+			return nil, err
+		}
+	}
+	best := cheapestSuppliers(suppliers)
+
+	suggestions := []models.ReorderSuggestion{}
+	for _, item := range inventory {
+		offer, supplied := best[item.ID]
+		if filter.SupplierID != 0 && !supplied {
+			continue
+		}
+
+		quantity := helper.ReorderQuantity(item, usage[item.ID], onOrder[item.ID], offer.supplier.LeadTimeDays, filter.HorizonDays)
+		if quantity == 0 {
+			continue
+		}
+
+		alert := stockAlert(item, usage[item.ID])
+		suggestion := models.ReorderSuggestion{
+			IngredientID:     item.ID,
+			Name:             item.Name,
+			Unit:             item.Unit,
+			Stock:            item.Stock,
+			OnOrder:          onOrder[item.ID],
+			ReorderThreshold: item.ReorderThreshold,
+			DailyUsage:       alert.DailyUsage,
+			DaysOfCover:      alert.DaysOfCover,
+			Quantity:         quantity,
+		}
+		if supplied {
+			id := offer.supplier.ID
+			suggestion.SupplierID = &id
+			suggestion.SupplierName = offer.supplier.Name
+			suggestion.LeadTimeDays = offer.supplier.LeadTimeDays
+			suggestion.UnitPrice = offer.price
+			suggestion.EstimatedCost = math.Round(quantity*offer.price*100) / 100
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i].DaysOfCover, suggestions[j].DaysOfCover
+		switch {
+		case a != nil && b != nil && *a != *b:
+			return *a < *b
+		case (a == nil) != (b == nil):
+			return a != nil
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	return suggestions, nil
+}
+
+type supplierOffer struct {
+	supplier models.Supplier
+	price    float64
+}
+
+// cheapestSuppliers выбирает для каждого ингредиента самого дешёвого поставщика,
+// при равной цене — с меньшим сроком поставки.
+func cheapestSuppliers(suppliers []models.Supplier) map[int]supplierOffer {
+	best := make(map[int]supplierOffer)
+	for _, supplier := range suppliers {
+		for _, item := range supplier.Items {
+			current, ok := best[item.IngredientID]
+			if ok && (current.price < item.UnitPrice ||
+				(current.price == item.UnitPrice && current.supplier.LeadTimeDays <= supplier.LeadTimeDays)) {
+				continue
+			}
+			best[item.IngredientID] = supplierOffer{supplier: supplier, price: item.UnitPrice}
+		}
+	}
+	return best
+}
+
+// CreateReorderDrafts превращает предложения в черновики заказов — по одному
+// на поставщика, все одной транзакцией. Предложения без поставщика пропускаются.
+func (s *svc) CreateReorderDrafts(req models.ReorderDraftRequest) ([]models.PurchaseOrder, error) {
+	suggestions, err := s.GetReorderSuggestions(req.ReorderFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	bySuggestion := make(map[int]models.ReorderSuggestion)
+	for _, suggestion := range suggestions {
+		bySuggestion[suggestion.IngredientID] = suggestion
+	}
+
+	selected := suggestions
+	if len(req.IngredientIDs) > 0 {
+		selected = nil
+		for _, id := range req.IngredientIDs {
+			suggestion, ok := bySuggestion[id]
+			if !ok {
+				return nil, fmt.Errorf("ingredient %d does not need to be reordered", id)
+			}
+			if suggestion.SupplierID == nil {
+				return nil, fmt.Errorf("ingredient %d has no supplier", id)
+			}
+			selected = append(selected, suggestion)
+		}
+	}
+
+	var supplierIDs []int
+	lines := make(map[int][]models.PurchaseOrderLine)
+	for _, suggestion := range selected {
+		if suggestion.SupplierID == nil {
+			continue
+		}
+		id := *suggestion.SupplierID
+		if _, ok := lines[id]; !ok {
+			supplierIDs = append(supplierIDs, id)
+		}
+		lines[id] = append(lines[id], models.PurchaseOrderLine{
+			IngredientID: suggestion.IngredientID,
+			Quantity:     suggestion.Quantity,
+			UnitPrice:    suggestion.UnitPrice,
+		})
+	}
+	if len(supplierIDs) == 0 {
+		return nil, fmt.Errorf("nothing to reorder")
+	}
+
+	note := req.Note
+	if note == "" {
+		note = "reorder suggestion"
+	}
+
+	drafts := make([]models.PurchaseOrder, 0, len(supplierIDs))
+	for _, id := range supplierIDs {
+		po := models.PurchaseOrder{SupplierID: id, Note: note, LocationID: req.LocationID, Lines: lines[id]}
+		if err := s.checkPurchaseOrder(&po); err != nil {
+			s.Log.Error("Invalid purchase order", "supplier_id", id, "error", err.Error())
+			return nil, err
+		}
+		drafts = append(drafts, po)
+	}
+
+	ids, err := s.Repo.SupplierRepo.CreatePurchaseOrders(drafts)
+	if err != nil {
+		s.Log.Error("Failed to create purchase orders", "error", err.Error())
+		return nil, err
+	}
+
+	orders := []models.PurchaseOrder{}
+	for _, id := range ids {
+		created, err := s.purchaseOrder(id)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *created)
+	}
+
+	s.Log.Info("Reorder drafts created", "purchase_orders", len(orders))
+	return orders, nil
+}
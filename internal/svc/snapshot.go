@@ -0,0 +1,120 @@
+package svc
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"frappuccino/internal/models"
+)
+
+// parseMoment разбирает момент снимка: дата YYYY-MM-DD означает конец этого дня
+// (остаток на закрытие), RFC 3339 — точный момент.
+func parseMoment(name, value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date.AddDate(0, 0, 1), nil
+	}
+	moment, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %s, expected YYYY-MM-DD or RFC 3339 time", name, value)
+	}
+	return moment, nil
+}
+
+// GetInventorySnapshot возвращает остатки на момент asOf, восстановленные по
+// журналу движений. locationID = 0 — по всем локациям.
+func (s *svc) GetInventorySnapshot(asOf string, locationID int) (*models.InventorySnapshot, error) {
+	moment, err := parseMoment("asOf", asOf)
+	if err != nil {
+		return nil, err
+	}
+	if locationID != 0 {
+		if err := s.checkLocation(&locationID); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := s.Repo.InventoryRepo.GetStockAsOf(moment, locationID)
+	if err != nil {
+		s.Log.Error("Failed to reconstruct inventory snapshot", "as_of", asOf, "error", err.Error())
+		return nil, err
+	}
+	for i := range items {
+		items[i].Stock = roundStock(items[i].Stock)
+	}
+
+	s.Log.Info("Inventory snapshot reconstructed", "as_of", moment, "location_id", locationID, "count", len(items))
+	return &models.InventorySnapshot{AsOf: moment, LocationID: locationID, Items: items}, nil
+}
+
+// GetInventoryDiff сравнивает остатки на моменты from и to и раскладывает
+// разницу по типам движений журнала. В отчёт попадают только позиции, которые
+// за это время двигались.
+func (s *svc) GetInventoryDiff(from, to string, locationID int) (*models.SnapshotDiff, error) {
+	start, err := parseMoment("from", from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseMoment("to", to)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+	if locationID != 0 {
+		if err := s.checkLocation(&locationID); err != nil {
+			return nil, err
+		}
+	}
+
+	before, err := s.Repo.InventoryRepo.GetStockAsOf(start, locationID)
+	if err != nil {
+		s.Log.Error("Failed to reconstruct inventory snapshot", "as_of", from, "error", err.Error())
+		return nil, err
+	}
+	after, err := s.Repo.InventoryRepo.GetStockAsOf(end, locationID)
+	if err != nil {
+		s.Log.Error("Failed to reconstruct inventory snapshot", "as_of", to, "error", err.Error())
+		return nil, err
+	}
+	movements, err := s.Repo.InventoryRepo.GetMovements(start, end, locationID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve inventory movements", "error", err.Error())
+		return nil, err
+	}
+
+	fromStock := make(map[int]float64, len(before))
+	for _, item := range before {
+		fromStock[item.IngredientID] = item.Stock
+	}
+
+	diff := &models.SnapshotDiff{From: start, To: end, LocationID: locationID, Items: []models.SnapshotDiffItem{}}
+	// Позиция без записей к моменту to не могла двигаться и раньше
+	for _, item := range after {
+		byType, moved := movements[item.IngredientID]
+		if !moved {
+			continue
+		}
+		for t, amount := range byType {
+			byType[t] = roundStock(amount)
+		}
+		diff.Items = append(diff.Items, models.SnapshotDiffItem{
+			IngredientID: item.IngredientID,
+			Name:         item.Name,
+			Unit:         item.Unit,
+			FromStock:    roundStock(fromStock[item.IngredientID]),
+			ToStock:      roundStock(item.Stock),
+			Change:       roundStock(item.Stock - fromStock[item.IngredientID]),
+			ByType:       byType,
+		})
+	}
+
+	s.Log.Info("Inventory diff calculated", "from", start, "to", end, "location_id", locationID, "count", len(diff.Items))
+	return diff, nil
+}
+
+// roundStock убирает хвосты сложения float: остатки хранятся с четырьмя знаками.
+func roundStock(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,169 @@
+package svc
+
+import (
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+// applyStock проставляет позициям portions_available и sold_out по текущим остаткам,
+// а заодно пищевую ценность порции — она считается по тем же рецептурам и складу.
+// Позиции меняются только в ответе; в базу флаги пишет syncStockAvailability.
+// С locationID остатки берутся только из этой локации — как при списании заказа,
+// 0 — общий остаток всех локаций.
+func (s *svc) applyStock(items []models.MenuItem, locationID int) error {
+	var inventoryMap map[int]models.InventoryItem
+	var err error
+	if locationID != 0 {
+		inventoryMap, err = s.locationInventoryMap(locationID)
+	} else {
+		inventoryMap, err = s.inventoryMap()
+	}
+	if err != nil {
+		return err
+	}
+
+	recipes, err := s.recipes(inventoryMap)
+	if err != nil {
+		return err
+	}
+
+	substitutes, err := s.Repo.InventoryRepo.GetSubstitutes()
+	if err != nil {
+		s.Log.Error("Failed to retrieve substitutes", "error", err.Error())
+		return err
+	}
+
+	for i := range items {
+		items[i].Nutrition = helper.ComputeNutrition(recipes[items[i].ID], inventoryMap)
+
+		portions := helper.PortionsAvailable(recipes[items[i].ID], inventoryMap, substitutes)
+		items[i].PortionsAvailable = portions
+		items[i].SoldOut = portions != nil && *portions == 0
+	}
+	return nil
+}
+
+// syncStockAvailability пересчитывает sold_out для всего меню после изменения остатков
+// и сохраняет изменившиеся флаги, чтобы позиция снималась с продажи сразу, как только
+// заканчивается нужный ингредиент, и возвращалась после поставки.
+// Ошибка только логируется: основная операция к этому моменту уже выполнена.
+func (s *svc) syncStockAvailability() {
+	items, err := s.Repo.MenuRepo.GetAllMenuItems()
+	if err != nil {
+		s.Log.Error("Failed to sync stock availability", "error", err.Error())
+		return
+	}
+	stored := make([]bool, len(items))
+	for i, item := range items {
+		stored[i] = item.SoldOut
+	}
+	if err := s.applyStock(items, 0); err != nil {
+		s.Log.Error("Failed to sync stock availability", "error", err.Error())
+		return
+	}
+
+	for i, item := range items {
+		if item.SoldOut == stored[i] {
+			continue
+		}
+		if err := s.Repo.MenuRepo.SetSoldOut(item.ID, item.SoldOut); err != nil {
+			s.Log.Error("Failed to update sold out flag", "id", item.ID, "error", err.Error())
+			return
+		}
+		s.Log.Info("Menu item stock availability changed", "id", item.ID, "name", item.Name, "sold_out", item.SoldOut)
+	}
+}
+
+// availableInventory возвращает склад, где Stock — доступный остаток без
+// просроченных партий: порции, заказы, замены и дозаказ считаются по нему.
+func (s *svc) availableInventory() ([]models.InventoryItem, error) {
+	inventory, err := s.Repo.InventoryRepo.GetInventory()
+	if err != nil {
+		s.Log.Error("Failed to retrieve inventory", "error", err.Error())
+		return nil, err
+	}
+	for i := range inventory {
+		inventory[i].Stock = inventory[i].AvailableStock
+	}
+	return inventory, nil
+}
+
+func (s *svc) inventoryMap() (map[int]models.InventoryItem, error) {
+	inventory, err := s.availableInventory()
+	if err != nil {
+		return nil, err
+	}
+
+	inventoryMap := make(map[int]models.InventoryItem)
+	for _, inv := range inventory {
+		inventoryMap[inv.ID] = inv
+	}
+	return inventoryMap, nil
+}
+
+// recipes возвращает рецептуры всех позиций в единицах склада; у комбо-наборов
+// это сумма рецептур компонентов.
+func (s *svc) recipes(inventory map[int]models.InventoryItem) (map[int][]models.MenuItemIngredient, error) {
+	recipes, err := s.Repo.MenuRepo.GetAllIngredients()
+	if err != nil {
+		s.Log.Error("Failed to retrieve recipes", "error", err.Error())
+		return nil, err
+	}
+
+	recipes, problems := helper.ConvertRecipes(recipes, inventory)
+	for _, problem := range problems {
+		s.Log.Warn("Recipe unit is not convertible, quantity used as is", "error", problem.Error())
+	}
+
+	components, err := s.Repo.MenuRepo.GetBundleComponents()
+	if err != nil {
+		s.Log.Error("Failed to retrieve bundle components", "error", err.Error())
+		return nil, err
+	}
+
+	return helper.ExpandRecipes(recipes, components), nil
+}
+
+// orderUsage переводит позиции заказа в списания ингредиентов по рецептурам
+// в единицах склада.
+// Ингредиенты, которых не хватает в локации заказа, заменяются по правилам замен;
+// сделанные замены записываются в позиции заказа.
+func (s *svc) orderUsage(data *models.Order) ([]models.IngredientUsage, error) {
+	inventoryMap, err := s.locationInventoryMap(data.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes, err := s.recipes(inventoryMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []models.IngredientUsage
+	for i, item := range data.Items {
+		for _, ing := range recipes[item.MenuItemID] {
+			usage = append(usage, models.IngredientUsage{
+				ItemIndex:    i,
+				IngredientID: ing.IngredientID,
+				Quantity:     ing.Quantity * float64(item.Quantity),
+			})
+		}
+	}
+
+	substitutes, err := s.Repo.InventoryRepo.GetSubstitutes()
+	if err != nil {
+		s.Log.Error("Failed to retrieve substitutes", "error", err.Error())
+		return nil, err
+	}
+
+	usage, made := helper.Substitute(usage, inventoryMap, substitutes)
+	for i, subs := range made {
+		data.Items[i].Substitutions = subs
+		for _, sub := range subs {
+			// Персоналу нужно знать, что напиток готовится не по рецептуре
+			s.Log.Warn("Ingredient substituted", "customer_id", data.CustomerID, "menu_item_id", data.Items[i].MenuItemID,
+				"ingredient", sub.IngredientName, "substitute", sub.SubstituteName, "quantity", sub.Quantity)
+		}
+	}
+	return usage, nil
+}
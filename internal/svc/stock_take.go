@@ -0,0 +1,166 @@
+package svc
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"frappuccino/internal/models"
+)
+
+// StartStockTake открывает инвентаризацию локации (по умолчанию основной).
+func (s *svc) StartStockTake(data models.StockTake) (*models.StockTake, error) {
+	if err := s.checkLocation(&data.LocationID); err != nil {
+		return nil, err
+	}
+
+	st, err := s.Repo.InventoryRepo.CreateStockTake(data)
+	if err != nil {
+		s.Log.Error("Failed to start stock-take", "location_id", data.LocationID, "error", err.Error())
+		return nil, err
+	}
+	s.Log.Info("Stock-take started", "id", st.ID, "location_id", st.LocationID)
+	return st, nil
+}
+
+func (s *svc) GetStockTakes() ([]models.StockTake, error) {
+	takes, err := s.Repo.InventoryRepo.GetStockTakes()
+	if err != nil {
+		s.Log.Error("Failed to retrieve stock-takes", "error", err.Error())
+		return nil, err
+	}
+	return takes, nil
+}
+
+func (s *svc) GetStockTake(id int) (models.StockTake, error) {
+	st, err := s.Repo.InventoryRepo.GetStockTake(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve stock-take", "id", id, "error", err.Error())
+		return models.StockTake{}, err
+	}
+	return st, nil
+}
+
+// SubmitStockCounts записывает пересчитанные остатки; ингредиент можно пересчитать
+// повторно, пока инвентаризация открыта.
+func (s *svc) SubmitStockCounts(id int, counts []models.StockCount) (models.StockTake, error) {
+	if len(counts) == 0 {
+		return models.StockTake{}, fmt.Errorf("counts must not be empty")
+	}
+	seen := make(map[int]bool)
+	for _, c := range counts {
+		if c.Counted < 0 {
+			return models.StockTake{}, fmt.Errorf("ingredient %d: counted quantity must not be negative, got: %v", c.IngredientID, c.Counted)
+		}
+		if seen[c.IngredientID] {
+			return models.StockTake{}, fmt.Errorf("ingredient %d is counted twice", c.IngredientID)
+		}
+		seen[c.IngredientID] = true
+	}
+
+	if err := s.Repo.InventoryRepo.SetStockCounts(id, counts); err != nil {
+		s.Log.Error("Failed to save stock counts", "id", id, "error", err.Error())
+		return models.StockTake{}, err
+	}
+	s.Log.Info("Stock counts saved", "id", id, "count", len(counts))
+	return s.GetStockTake(id)
+}
+
+// GetVarianceReport сравнивает подсчёт с учётом и расход по рецептурам с
+// фактическим расходом с прошлой зафиксированной инвентаризации той же локации.
+func (s *svc) GetVarianceReport(id int) (*models.VarianceReport, error) {
+	st, err := s.GetStockTake(id)
+	if err != nil {
+		return nil, err
+	}
+
+	takes, err := s.GetStockTakes()
+	if err != nil {
+		return nil, err
+	}
+	var since *time.Time
+	for _, t := range takes {
+		if t.ID == st.ID || t.LocationID != st.LocationID || t.Status != models.StockTakeCommitted || t.CommittedAt.After(st.StartedAt) {
+			continue
+		}
+		if since == nil || t.CommittedAt.After(*since) {
+			since = t.CommittedAt
+		}
+	}
+	until := time.Now()
+	if st.CommittedAt != nil {
+		until = *st.CommittedAt
+	}
+
+	usage, err := s.Repo.InventoryRepo.GetRecipeUsage(st.LocationID, since, until)
+	if err != nil {
+		s.Log.Error("Failed to retrieve recipe usage", "id", id, "error", err.Error())
+		return nil, err
+	}
+
+	inventory, err := s.Repo.InventoryRepo.GetInventory()
+	if err != nil {
+		s.Log.Error("Failed to retrieve inventory", "error", err.Error())
+		return nil, err
+	}
+	prices := make(map[int]float64)
+	for _, item := range inventory {
+		prices[item.ID] = item.Price
+	}
+
+	report := &models.VarianceReport{
+		StockTakeID: st.ID,
+		Status:      st.Status,
+		UsageSince:  since,
+		Counted:     len(st.Counts),
+		Uncounted:   len(inventory) - len(st.Counts),
+		Items:       []models.StockVariance{},
+	}
+	for _, c := range st.Counts {
+		variance := math.Round((c.Counted-c.SystemQuantity)*10000) / 10000
+		item := models.StockVariance{
+			IngredientID:     c.IngredientID,
+			Name:             c.Name,
+			Unit:             c.Unit,
+			SystemQuantity:   c.SystemQuantity,
+			Counted:          c.Counted,
+			Variance:         variance,
+			ValueDifference:  math.Round(variance*prices[c.IngredientID]*100) / 100,
+			TheoreticalUsage: math.Round(usage[c.IngredientID]*10000) / 10000,
+		}
+		// Недостача — это расход, который не прошёл через заказы
+		item.ActualUsage = math.Round((item.TheoreticalUsage-variance)*10000) / 10000
+		report.TotalValueDifference += item.ValueDifference
+		report.Items = append(report.Items, item)
+	}
+	report.TotalValueDifference = math.Round(report.TotalValueDifference*100) / 100
+	return report, nil
+}
+
+// CommitStockTake проводит расхождения корректировками и закрывает инвентаризацию.
+func (s *svc) CommitStockTake(id int) (*models.VarianceReport, error) {
+	transactions, err := s.Repo.InventoryRepo.CommitStockTake(id)
+	if err != nil {
+		s.Log.Error("Failed to commit stock-take", "id", id, "error", err.Error())
+		return nil, err
+	}
+
+	changes := make(map[int]float64)
+	for _, t := range transactions {
+		changes[t.IngredientID] = t.ChangeAmount
+	}
+	s.syncStockAvailability()
+	s.notifyLowStock(changes)
+
+	s.Log.Info("Stock-take committed", "id", id, "adjustments", len(transactions))
+	return s.GetVarianceReport(id)
+}
+
+func (s *svc) CancelStockTake(id int) error {
+	if err := s.Repo.InventoryRepo.CancelStockTake(id); err != nil {
+		s.Log.Error("Failed to cancel stock-take", "id", id, "error", err.Error())
+		return err
+	}
+	s.Log.Info("Stock-take cancelled", "id", id)
+	return nil
+}
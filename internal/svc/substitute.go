@@ -0,0 +1,76 @@
+package svc
+
+import (
+	"fmt"
+
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetSubstitutes(ingredientID int) ([]models.IngredientSubstitute, error) {
+	if _, err := s.Repo.InventoryRepo.GetInventoryId(ingredientID); err != nil {
+		s.Log.Error("Failed to fetch inventory item by ID", "id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+
+	subs, err := s.Repo.InventoryRepo.GetSubstitutesByIngredientID(ingredientID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve substitutes", "ingredient_id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+	return subs, nil
+}
+
+// SetSubstitute разрешает заменять ингредиент другим. Ratio задаётся в единицах
+// склада обоих ингредиентов: сколько замены уходит на единицу исходного.
+func (s *svc) SetSubstitute(ingredientID int, sub models.IngredientSubstitute) (*models.IngredientSubstitute, error) {
+	sub.IngredientID = ingredientID
+	if sub.SubstituteID == ingredientID {
+		return nil, fmt.Errorf("an ingredient cannot substitute itself")
+	}
+	if sub.Ratio <= 0 {
+		return nil, fmt.Errorf("ratio must be greater than 0, got: %v", sub.Ratio)
+	}
+
+	if _, err := s.Repo.InventoryRepo.GetInventoryId(ingredientID); err != nil {
+		s.Log.Error("Failed to fetch inventory item by ID", "id", ingredientID, "error", err.Error())
+		return nil, err
+	}
+	substitute, err := s.Repo.InventoryRepo.GetInventoryId(sub.SubstituteID)
+	if err != nil {
+		s.Log.Error("Unknown substitute", "substitute_id", sub.SubstituteID, "error", err.Error())
+		return nil, fmt.Errorf("substitute with ID %d not found in inventory", sub.SubstituteID)
+	}
+
+	if err := s.Repo.InventoryRepo.SetSubstitute(sub); err != nil {
+		s.Log.Error("Failed to save substitute", "ingredient_id", ingredientID, "substitute_id", sub.SubstituteID, "error", err.Error())
+		return nil, err
+	}
+
+	// С новой заменой позиции, снятые с продажи из-за нехватки, могут вернуться
+	s.syncStockAvailability()
+
+	sub.SubstituteName = substitute.Name
+	s.Log.Info("Substitute saved", "ingredient_id", ingredientID, "substitute_id", sub.SubstituteID, "ratio", sub.Ratio)
+	return &sub, nil
+}
+
+func (s *svc) DeleteSubstitute(ingredientID, substituteID int) error {
+	if err := s.Repo.InventoryRepo.DeleteSubstitute(ingredientID, substituteID); err != nil {
+		s.Log.Error("Failed to delete substitute", "ingredient_id", ingredientID, "substitute_id", substituteID, "error", err.Error())
+		return err
+	}
+
+	s.syncStockAvailability()
+
+	s.Log.Info("Substitute deleted", "ingredient_id", ingredientID, "substitute_id", substituteID)
+	return nil
+}
+
+func (s *svc) GetSubstitutionReport(startDate, endDate string) ([]models.SubstitutionRecord, error) {
+	records, err := s.Repo.OrderRepo.GetSubstitutions(startDate, endDate)
+	if err != nil {
+		s.Log.Error("Failed to retrieve substitutions", "error", err.Error())
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,104 @@
+package svc
+
+import (
+	"fmt"
+	"strings"
+
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetSuppliers() ([]models.Supplier, error) {
+	suppliers, err := s.Repo.SupplierRepo.GetAll()
+	if err != nil {
+		s.Log.Error("Failed to retrieve suppliers", "error", err.Error())
+		return nil, err
+	}
+	return suppliers, nil
+}
+
+func (s *svc) GetSupplier(id int) (models.Supplier, error) {
+	supplier, err := s.Repo.SupplierRepo.GetByID(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve supplier", "id", id, "error", err.Error())
+		return models.Supplier{}, err
+	}
+	return supplier, nil
+}
+
+func (s *svc) CreateSupplier(supplier models.Supplier) (*models.Supplier, error) {
+	if supplier.ID != 0 {
+		return nil, fmt.Errorf("supplier ID should not be set when adding a new supplier")
+	}
+	if err := s.checkSupplier(&supplier); err != nil {
+		return nil, err
+	}
+
+	created, err := s.Repo.SupplierRepo.Create(supplier)
+	if err != nil {
+		s.Log.Error("Failed to create supplier", "name", supplier.Name, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Supplier created", "id", created.ID, "name", created.Name)
+	return created, nil
+}
+
+func (s *svc) UpdateSupplier(id int, supplier models.Supplier) error {
+	if err := s.checkSupplier(&supplier); err != nil {
+		return err
+	}
+
+	if err := s.Repo.SupplierRepo.Update(id, supplier); err != nil {
+		s.Log.Error("Failed to update supplier", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Supplier updated", "id", id)
+	return nil
+}
+
+func (s *svc) DeleteSupplier(id int) error {
+	if err := s.Repo.SupplierRepo.Delete(id); err != nil {
+		s.Log.Error("Failed to delete supplier", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Supplier deleted", "id", id)
+	return nil
+}
+
+// checkSupplier проверяет карточку и прайс: каждый ингредиент есть на складе
+// и указан один раз.
+func (s *svc) checkSupplier(supplier *models.Supplier) error {
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	if supplier.Name == "" {
+		return fmt.Errorf("please provide a name for the supplier")
+	}
+	supplier.Email = strings.TrimSpace(supplier.Email)
+	if supplier.Email != "" && !strings.Contains(supplier.Email, "@") {
+		return fmt.Errorf("invalid supplier email: %s", supplier.Email)
+	}
+	if supplier.LeadTimeDays < 0 {
+		return fmt.Errorf("lead_time_days cannot be negative, got: %d", supplier.LeadTimeDays)
+	}
+
+	inventoryMap, err := s.inventoryMap()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool)
+	for _, item := range supplier.Items {
+		if _, ok := inventoryMap[item.IngredientID]; !ok {
+			return fmt.Errorf("ingredient with ID %d not found in inventory", item.IngredientID)
+		}
+		if seen[item.IngredientID] {
+			return fmt.Errorf("ingredient %d is listed more than once", item.IngredientID)
+		}
+		seen[item.IngredientID] = true
+		if item.UnitPrice < 0 {
+			return fmt.Errorf("unit_price of ingredient %d cannot be negative", item.IngredientID)
+		}
+	}
+	return nil
+}
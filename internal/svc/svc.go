@@ -1,18 +1,22 @@
 package svc
 
 import (
+	"io"
 	"log/slog"
 	"os"
+	"time"
 
 	"frappuccino/internal/models"
 	repo "frappuccino/internal/repo"
+	"frappuccino/pkg/notify"
+	"frappuccino/pkg/storage"
 )
 
 type Svc interface {
 	CreateInventory(data models.InventoryItem) error
 	InventoriesGet() ([]models.InventoryItem, error)
 	InventoryGetId(id int) (models.InventoryItem, error)
-	InventoryUpDate(id int, data models.InventoryItem) error
+	InventoryUpDate(id int, data models.InventoryItem, locationID int) error
 	DeleteInvent(id int) error
 	CreateMenuItem(item models.MenuItem) (*models.MenuItem, error)
 	GetAllMenuItems() ([]models.MenuItem, error)
@@ -32,18 +36,108 @@ type Svc interface {
 	GetNumberOfOrderedItems(startDate, endDate string) (map[string]int, error)
 	GetOrderedItemsByPeriod(period, month, year string) ([]models.OrderedItemReport, error)
 	BatchProcessOrders(orders []models.Order) (*models.BatchOrderResponse, error)
-	GetLeftOvers(sortBy string, page int, pageSize int) (*models.InventoryResponse, error)
+	GetLeftOvers(filter models.LeftOverFilter) (*models.InventoryResponse, error)
+	GetPriceHistory(menuItemID int) ([]models.PriceHistory, error)
+	SchedulePriceChange(menuItemID int, change models.ScheduledPriceChange) (*models.ScheduledPriceChange, error)
+	GetScheduledPriceChanges(menuItemID int) ([]models.ScheduledPriceChange, error)
+	CancelScheduledPriceChange(menuItemID, changeID int) error
+	ApplyScheduledPriceChanges() (int, error)
+	RunPriceScheduler(interval time.Duration)
+	ListMenu(filter models.MenuFilter) ([]models.MenuItem, error)
+	GetAvailability(menuItemID int) ([]models.AvailabilityWindow, error)
+	SetAvailability(menuItemID int, windows []models.AvailabilityWindow) ([]models.AvailabilityWindow, error)
+	GetPriceRules() ([]models.PriceRule, error)
+	GetPriceRule(id int) (models.PriceRule, error)
+	CreatePriceRule(rule models.PriceRule) (*models.PriceRule, error)
+	UpdatePriceRule(id int, rule models.PriceRule) error
+	DeletePriceRule(id int) error
+	GetCategories() ([]models.Category, error)
+	GetCategory(id int) (models.Category, error)
+	CreateCategory(category models.Category) (*models.Category, error)
+	UpdateCategory(id int, category models.Category) error
+	DeleteCategory(id int) error
+	GetMenuTree(filter models.MenuFilter) (*models.MenuTree, error)
+	GetMenuDraft() (*models.MenuDraft, error)
+	StageMenuChange(change models.MenuDraftChange) (*models.MenuDraftChange, error)
+	DeleteMenuChange(id int) error
+	DiscardMenuDraft() error
+	PublishMenuDraft(note string) (*models.MenuVersion, error)
+	GetMenuVersions() ([]models.MenuVersion, error)
+	GetMenuVersion(id int) (*models.MenuVersion, error)
+	DiffMenuVersions(from, to int) (*models.MenuVersionDiff, error)
+	RollbackMenu(versionID int) (*models.MenuVersion, error)
+	GetTranslations(menuItemID int) ([]models.MenuItemTranslation, error)
+	SetTranslation(menuItemID int, locale string, t models.MenuItemTranslation) (*models.MenuItemTranslation, error)
+	DeleteTranslation(menuItemID int, locale string) error
+	UploadMenuItemImages(menuItemID int, files []models.ImageUpload) ([]models.MenuItemImage, error)
+	GetMenuItemImages(menuItemID int) ([]models.MenuItemImage, error)
+	DeleteMenuItemImage(menuItemID, imageID int) error
+	OpenMedia(name string) (io.ReadSeekCloser, error)
+	ExportMenu() ([]models.MenuExportItem, error)
+	ImportMenu(data io.Reader, format string, dryRun bool) (*models.MenuImportResult, error)
+	ExportInventory() ([]models.InventoryExportItem, error)
+	ImportInventory(data io.Reader, format, mode string, locationID int, dryRun bool) (*models.InventoryImportResult, error)
+	GetInventorySnapshot(asOf string, locationID int) (*models.InventorySnapshot, error)
+	GetInventoryDiff(from, to string, locationID int) (*models.SnapshotDiff, error)
+	GetSubstitutes(ingredientID int) ([]models.IngredientSubstitute, error)
+	SetSubstitute(ingredientID int, sub models.IngredientSubstitute) (*models.IngredientSubstitute, error)
+	DeleteSubstitute(ingredientID, substituteID int) error
+	GetSubstitutionReport(startDate, endDate string) ([]models.SubstitutionRecord, error)
+	GetInventoryTransactions(ingredientID int, filter models.TransactionFilter) ([]models.InventoryTransaction, error)
+	RecordInventoryTransaction(ingredientID int, t models.InventoryTransaction) (*models.InventoryTransaction, error)
+	CheckInventoryLedger() (*models.LedgerCheck, error)
+	GetStockAlerts() ([]models.StockAlert, error)
+	GetSuppliers() ([]models.Supplier, error)
+	GetSupplier(id int) (models.Supplier, error)
+	CreateSupplier(supplier models.Supplier) (*models.Supplier, error)
+	UpdateSupplier(id int, supplier models.Supplier) error
+	DeleteSupplier(id int) error
+	GetPurchaseOrders(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, error)
+	GetPurchaseOrder(id int) (models.PurchaseOrder, error)
+	CreatePurchaseOrder(po models.PurchaseOrder) (*models.PurchaseOrder, error)
+	UpdatePurchaseOrder(id int, po models.PurchaseOrder) error
+	DeletePurchaseOrder(id int) error
+	SendPurchaseOrder(id int) (*models.PurchaseOrder, error)
+	ReceivePurchaseOrder(id int, receipt models.PurchaseReceipt) (*models.PurchaseOrder, error)
+	GetReorderSuggestions(filter models.ReorderFilter) ([]models.ReorderSuggestion, error)
+	CreateReorderDrafts(req models.ReorderDraftRequest) ([]models.PurchaseOrder, error)
+	GetIngredientForecast(days, weeks int) (*models.ForecastReport, error)
+	RecordWaste(ingredientID int, entry models.WasteEntry) (*models.InventoryTransaction, error)
+	GetWasteReport(filter models.WasteFilter) (*models.WasteReport, error)
+	GetInventoryLots(ingredientID, locationID int, all bool) ([]models.InventoryLot, error)
+	GetExpiringLots(days, locationID int) ([]models.InventoryLot, error)
+	StartStockTake(data models.StockTake) (*models.StockTake, error)
+	GetStockTakes() ([]models.StockTake, error)
+	GetStockTake(id int) (models.StockTake, error)
+	SubmitStockCounts(id int, counts []models.StockCount) (models.StockTake, error)
+	GetVarianceReport(id int) (*models.VarianceReport, error)
+	CommitStockTake(id int) (*models.VarianceReport, error)
+	CancelStockTake(id int) error
+	GetInventoryValuation(filter models.ValuationFilter) (*models.ValuationReport, error)
+	GetLocations() ([]models.Location, error)
+	GetLocation(id int) (models.Location, error)
+	CreateLocation(location models.Location) (*models.Location, error)
+	GetLocationStock(id int) ([]models.LocationStock, error)
+	GetTransfers(filter models.TransferFilter) ([]models.StockTransfer, error)
+	GetTransfer(id int) (models.StockTransfer, error)
+	CreateTransfer(data models.StockTransfer) (*models.StockTransfer, error)
+	ReceiveTransfer(id int) (*models.StockTransfer, error)
+	CancelTransfer(id int) (*models.StockTransfer, error)
 }
 
 type svc struct {
-	Repo *repo.Container
-	Log  *slog.Logger
+	Repo     *repo.Container
+	Log      *slog.Logger
+	Media    storage.Storage
+	Notifier notify.Notifier
 }
 
-func NewSvc(r *repo.Container) Svc {
+func NewSvc(r *repo.Container, media storage.Storage, notifier notify.Notifier) Svc {
 	loger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	return &svc{
-		Repo: r,
-		Log:  loger,
+		Repo:     r,
+		Log:      loger,
+		Media:    media,
+		Notifier: notifier,
 	}
 }
@@ -0,0 +1,110 @@
+package svc
+
+import (
+	"fmt"
+
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetTransfers(filter models.TransferFilter) ([]models.StockTransfer, error) {
+	switch filter.Status {
+	case "", models.TransferInTransit, models.TransferReceived, models.TransferCancelled:
+	default:
+		return nil, fmt.Errorf("invalid status: %s, expected in_transit, received or cancelled", filter.Status)
+	}
+
+	transfers, err := s.Repo.InventoryRepo.GetTransfers(filter)
+	if err != nil {
+		s.Log.Error("Failed to retrieve transfers", "error", err.Error())
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (s *svc) GetTransfer(id int) (models.StockTransfer, error) {
+	transfer, err := s.Repo.InventoryRepo.GetTransfer(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve transfer", "id", id, "error", err.Error())
+		return models.StockTransfer{}, err
+	}
+	return transfer, nil
+}
+
+// CreateTransfer отправляет товар из одной локации в другую. Одинаковые
+// ингредиенты в строках складываются.
+func (s *svc) CreateTransfer(data models.StockTransfer) (*models.StockTransfer, error) {
+	if data.FromLocationID == 0 || data.ToLocationID == 0 {
+		return nil, fmt.Errorf("from_location_id and to_location_id are required")
+	}
+	if data.FromLocationID == data.ToLocationID {
+		return nil, fmt.Errorf("from_location_id and to_location_id must differ")
+	}
+	if err := s.checkLocation(&data.FromLocationID); err != nil {
+		return nil, err
+	}
+	if err := s.checkLocation(&data.ToLocationID); err != nil {
+		return nil, err
+	}
+	if len(data.Lines) == 0 {
+		return nil, fmt.Errorf("transfer must have at least one line")
+	}
+
+	var lines []models.TransferLine
+	index := make(map[int]int)
+	for _, line := range data.Lines {
+		if line.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity of ingredient %d must be greater than 0", line.IngredientID)
+		}
+		if n, ok := index[line.IngredientID]; ok {
+			lines[n].Quantity += line.Quantity
+			continue
+		}
+		index[line.IngredientID] = len(lines)
+		lines = append(lines, models.TransferLine{IngredientID: line.IngredientID, Quantity: line.Quantity})
+	}
+	data.Lines = lines
+
+	id, err := s.Repo.InventoryRepo.CreateTransfer(data)
+	if err != nil {
+		s.Log.Error("Failed to create transfer", "from", data.FromLocationID, "to", data.ToLocationID, "error", err.Error())
+		return nil, err
+	}
+
+	s.syncStockAvailability()
+	changes := make(map[int]float64)
+	for _, line := range lines {
+		changes[line.IngredientID] = -line.Quantity
+	}
+	s.notifyLowStock(changes)
+
+	s.Log.Info("Transfer sent", "id", id, "from", data.FromLocationID, "to", data.ToLocationID)
+	return s.transfer(id)
+}
+
+func (s *svc) ReceiveTransfer(id int) (*models.StockTransfer, error) {
+	if err := s.Repo.InventoryRepo.ReceiveTransfer(id); err != nil {
+		s.Log.Error("Failed to receive transfer", "id", id, "error", err.Error())
+		return nil, err
+	}
+	s.syncStockAvailability()
+	s.Log.Info("Transfer received", "id", id)
+	return s.transfer(id)
+}
+
+func (s *svc) CancelTransfer(id int) (*models.StockTransfer, error) {
+	if err := s.Repo.InventoryRepo.CancelTransfer(id); err != nil {
+		s.Log.Error("Failed to cancel transfer", "id", id, "error", err.Error())
+		return nil, err
+	}
+	s.syncStockAvailability()
+	s.Log.Info("Transfer cancelled", "id", id)
+	return s.transfer(id)
+}
+
+func (s *svc) transfer(id int) (*models.StockTransfer, error) {
+	transfer, err := s.GetTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
@@ -0,0 +1,79 @@
+package svc
+
+import (
+	"fmt"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+func (s *svc) GetTranslations(menuItemID int) ([]models.MenuItemTranslation, error) {
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	translations, err := s.Repo.MenuRepo.GetTranslationsByMenuItemID(menuItemID)
+	if err != nil {
+		s.Log.Error("Failed to retrieve translations", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (s *svc) SetTranslation(menuItemID int, locale string, t models.MenuItemTranslation) (*models.MenuItemTranslation, error) {
+	locale, err := helper.NormalizeLocale(locale)
+	if err != nil {
+		s.Log.Error("Invalid locale", "locale", locale, "error", err.Error())
+		return nil, err
+	}
+	if locale == models.DefaultLocale {
+		return nil, fmt.Errorf("%q is the default language, update the menu item itself instead", locale)
+	}
+	if err := helper.IsValidName(t.Name); err != nil {
+		s.Log.Error("Invalid translated name", "error", err.Error())
+		return nil, err
+	}
+
+	if _, err := s.Repo.MenuRepo.GetMenuItemByID(menuItemID); err != nil {
+		s.Log.Error("Failed to retrieve menu item", "id", menuItemID, "error", err.Error())
+		return nil, err
+	}
+
+	t.MenuItemID, t.Locale = menuItemID, locale
+	if err := s.Repo.MenuRepo.SetTranslation(t); err != nil {
+		s.Log.Error("Failed to save translation", "id", menuItemID, "locale", locale, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Translation saved", "id", menuItemID, "locale", locale)
+	return &t, nil
+}
+
+func (s *svc) DeleteTranslation(menuItemID int, locale string) error {
+	locale, err := helper.NormalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.MenuRepo.DeleteTranslation(menuItemID, locale); err != nil {
+		s.Log.Error("Failed to delete translation", "id", menuItemID, "locale", locale, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Translation deleted", "id", menuItemID, "locale", locale)
+	return nil
+}
+
+// localize переводит позиции на предпочитаемый язык клиента.
+func (s *svc) localize(items []models.MenuItem, languages []string) error {
+	translations, err := s.Repo.MenuRepo.GetTranslations()
+	if err != nil {
+		s.Log.Error("Failed to retrieve translations", "error", err.Error())
+		return err
+	}
+	for i := range items {
+		helper.Localize(&items[i], translations[items[i].ID], languages)
+	}
+	return nil
+}
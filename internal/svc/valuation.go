@@ -0,0 +1,121 @@
+package svc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+// GetInventoryValuation оценивает остатки методом FIFO или по средневзвешенной
+// цене и считает себестоимость расхода по заказам за период. Журнал прогоняется
+// с самого начала, фильтр дат ограничивает только себестоимость.
+func (s *svc) GetInventoryValuation(filter models.ValuationFilter) (*models.ValuationReport, error) {
+	if filter.Method == "" {
+		filter.Method = models.ValuationFIFO
+	}
+	if filter.Method != models.ValuationFIFO && filter.Method != models.ValuationWAC {
+		return nil, fmt.Errorf("invalid method: %s, expected fifo or wac", filter.Method)
+	}
+	if filter.Period == "" {
+		filter.Period = "month"
+	}
+	switch filter.Period {
+	case "day", "week", "month":
+	default:
+		return nil, fmt.Errorf("invalid period: %s, expected day, week or month", filter.Period)
+	}
+
+	var start, end time.Time
+	if filter.StartDate != "" {
+		date, err := time.Parse(time.DateOnly, filter.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid startDate: %s, expected YYYY-MM-DD", filter.StartDate)
+		}
+		start = date
+	}
+	if filter.EndDate != "" {
+		date, err := time.Parse(time.DateOnly, filter.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endDate: %s, expected YYYY-MM-DD", filter.EndDate)
+		}
+		// Конечная дата включается целиком
+		end = date.AddDate(0, 0, 1)
+	}
+
+	inventory, err := s.Repo.InventoryRepo.GetInventory()
+	if err != nil {
+		s.Log.Error("Failed to retrieve inventory", "error", err.Error())
+		return nil, err
+	}
+	ledger, err := s.Repo.InventoryRepo.GetCostLedger()
+	if err != nil {
+		s.Log.Error("Failed to retrieve cost ledger", "error", err.Error())
+		return nil, err
+	}
+	byIngredient := make(map[int][]models.InventoryTransaction)
+	for _, t := range ledger {
+		byIngredient[t.IngredientID] = append(byIngredient[t.IngredientID], t)
+	}
+
+	report := &models.ValuationReport{
+		Method:    filter.Method,
+		AsOf:      time.Now(),
+		StartDate: filter.StartDate,
+		EndDate:   filter.EndDate,
+		Period:    filter.Period,
+		Items:     []models.ItemValuation{},
+		COGS:      []models.COGSPeriod{},
+	}
+	cogs := make(map[string]*models.COGSPeriod)
+	for _, item := range inventory {
+		rows := byIngredient[item.ID]
+		cost := helper.CostLedger(filter.Method, rows, item.Price)
+
+		for i, t := range rows {
+			if t.Type != models.TransactionUse {
+				continue
+			}
+			if (!start.IsZero() && t.OccurredAt.Before(start)) || (!end.IsZero() && !t.OccurredAt.Before(end)) {
+				continue
+			}
+			key := helper.PeriodKey(t.OccurredAt, filter.Period)
+			if cogs[key] == nil {
+				cogs[key] = &models.COGSPeriod{Period: key, ByItem: make(map[string]float64)}
+			}
+			cogs[key].Cost += cost.Costs[i]
+			cogs[key].ByItem[item.Name] += cost.Costs[i]
+		}
+
+		if math.Round(cost.Stock*10000) == 0 {
+			continue
+		}
+		valuation := models.ItemValuation{
+			IngredientID: item.ID,
+			Name:         item.Name,
+			Unit:         item.Unit,
+			Stock:        math.Round(cost.Stock*10000) / 10000,
+			UnitCost:     math.Round(cost.Value/cost.Stock*10000) / 10000,
+			Value:        math.Round(cost.Value*100) / 100,
+		}
+		report.TotalValue += valuation.Value
+		report.Items = append(report.Items, valuation)
+	}
+
+	for _, period := range cogs {
+		period.Cost = math.Round(period.Cost*100) / 100
+		for name, cost := range period.ByItem {
+			period.ByItem[name] = math.Round(cost*100) / 100
+		}
+		report.TotalCOGS += period.Cost
+		report.COGS = append(report.COGS, *period)
+	}
+	sort.Slice(report.COGS, func(i, j int) bool { return report.COGS[i].Period < report.COGS[j].Period })
+
+	report.TotalValue = math.Round(report.TotalValue*100) / 100
+	report.TotalCOGS = math.Round(report.TotalCOGS*100) / 100
+	return report, nil
+}
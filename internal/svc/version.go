@@ -0,0 +1,300 @@
+package svc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+	"frappuccino/pkg/cerrors"
+)
+
+// GetMenuDraft возвращает изменения черновика и меню, каким оно станет после публикации.
+func (s *svc) GetMenuDraft() (*models.MenuDraft, error) {
+	changes, err := s.Repo.MenuRepo.GetDraftChanges()
+	if err != nil {
+		s.Log.Error("Failed to retrieve draft changes", "error", err.Error())
+		return nil, err
+	}
+
+	items, err := s.liveMenuSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MenuDraft{Changes: changes, Preview: previewDraft(items, changes)}, nil
+}
+
+func (s *svc) StageMenuChange(change models.MenuDraftChange) (*models.MenuDraftChange, error) {
+	if err := s.checkDraftChange(&change); err != nil {
+		s.Log.Error("Invalid draft change", "action", change.Action, "error", err.Error())
+		return nil, err
+	}
+
+	staged, err := s.Repo.MenuRepo.CreateDraftChange(change)
+	if err != nil {
+		s.Log.Error("Failed to stage draft change", "action", change.Action, "error", err.Error())
+		return nil, err
+	}
+
+	s.Log.Info("Draft change staged", "id", staged.ID, "action", staged.Action)
+	return staged, nil
+}
+
+func (s *svc) DeleteMenuChange(id int) error {
+	if err := s.Repo.MenuRepo.DeleteDraftChange(id); err != nil {
+		s.Log.Error("Failed to delete draft change", "id", id, "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Draft change deleted", "id", id)
+	return nil
+}
+
+func (s *svc) DiscardMenuDraft() error {
+	if err := s.Repo.MenuRepo.DiscardDraft(); err != nil {
+		s.Log.Error("Failed to discard draft", "error", err.Error())
+		return err
+	}
+
+	s.Log.Info("Draft discarded")
+	return nil
+}
+
+// PublishMenuDraft публикует черновик одной транзакцией. Пустой черновик тоже
+// публикуется — так можно зафиксировать текущее меню как версию.
+func (s *svc) PublishMenuDraft(note string) (*models.MenuVersion, error) {
+	images := s.imageSnapshot()
+	version, err := s.Repo.MenuRepo.PublishDraft(note)
+	if err != nil {
+		s.Log.Error("Failed to publish draft", "error", err.Error())
+		return nil, err
+	}
+
+	s.removeDroppedImages(images)
+	s.syncStockAvailability()
+
+	s.Log.Info("Menu version published", "version", version.ID, "items", version.ItemCount)
+	return version, nil
+}
+
+func (s *svc) GetMenuVersions() ([]models.MenuVersion, error) {
+	versions, err := s.Repo.MenuRepo.GetMenuVersions()
+	if err != nil {
+		s.Log.Error("Failed to retrieve menu versions", "error", err.Error())
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (s *svc) GetMenuVersion(id int) (*models.MenuVersion, error) {
+	version, err := s.Repo.MenuRepo.GetMenuVersion(id)
+	if err != nil {
+		s.Log.Error("Failed to retrieve menu version", "id", id, "error", err.Error())
+		return nil, err
+	}
+	return version, nil
+}
+
+func (s *svc) DiffMenuVersions(from, to int) (*models.MenuVersionDiff, error) {
+	fromVersion, err := s.GetMenuVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.GetMenuVersion(to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.MenuVersionDiff{From: from, To: to}
+	diff.Added, diff.Removed, diff.Changed = helper.DiffMenus(fromVersion.Items, toVersion.Items)
+	return diff, nil
+}
+
+func (s *svc) RollbackMenu(versionID int) (*models.MenuVersion, error) {
+	images := s.imageSnapshot()
+	version, err := s.Repo.MenuRepo.RollbackToVersion(versionID, fmt.Sprintf("rollback to version %d", versionID))
+	if err != nil {
+		s.Log.Error("Failed to roll back menu", "version", versionID, "error", err.Error())
+		return nil, err
+	}
+
+	s.removeDroppedImages(images)
+	s.syncStockAvailability()
+
+	s.Log.Info("Menu rolled back", "to_version", versionID, "new_version", version.ID)
+	return version, nil
+}
+
+// checkDraftChange проверяет изменение так же, как CreateMenuItem/UpdateMenuItem,
+// чтобы ошибки всплывали при подготовке черновика, а не при публикации.
+func (s *svc) checkDraftChange(change *models.MenuDraftChange) error {
+	switch change.Action {
+	case models.DraftActionCreate:
+		if change.MenuItemID != nil {
+			return fmt.Errorf("menu_item_id should not be set when staging a new menu item")
+		}
+		if change.Item == nil {
+			return fmt.Errorf("please provide the menu item to create")
+		}
+		item := change.Item
+		item.ID = 0
+		if item.Type == "" {
+			item.Type = models.MenuItemSingle
+		}
+		if item.Type != models.MenuItemSingle && item.Type != models.MenuItemBundle {
+			return fmt.Errorf("menu item type must be %q or %q, got: %q", models.MenuItemSingle, models.MenuItemBundle, item.Type)
+		}
+		if item.Type == models.MenuItemBundle {
+			if err := s.checkBundle(*item); err != nil {
+				return err
+			}
+		}
+		return s.checkDraftItem(item, item.Ingredients)
+
+	case models.DraftActionUpdate:
+		if change.MenuItemID == nil {
+			return fmt.Errorf("please provide menu_item_id of the item to update")
+		}
+		if change.Item == nil {
+			return fmt.Errorf("please provide the updated menu item")
+		}
+		existing, err := s.Repo.MenuRepo.GetMenuItemByID(*change.MenuItemID)
+		if err != nil {
+			return err
+		}
+		item := change.Item
+		if item.Type != "" && item.Type != existing.Type {
+			return fmt.Errorf("menu item type cannot be changed from %q to %q", existing.Type, item.Type)
+		}
+		item.ID, item.Type = existing.ID, existing.Type
+		if item.Type == models.MenuItemBundle && item.Components != nil {
+			if err := s.checkBundle(*item); err != nil {
+				return err
+			}
+		}
+		return s.checkDraftItem(item, item.Ingredients)
+
+	case models.DraftActionDelete:
+		if change.MenuItemID == nil {
+			return fmt.Errorf("please provide menu_item_id of the item to delete")
+		}
+		change.Item = nil
+		if _, err := s.Repo.MenuRepo.GetMenuItemByID(*change.MenuItemID); err != nil {
+			return err
+		}
+		return s.checkNotInBundles(*change.MenuItemID)
+
+	default:
+		return fmt.Errorf("draft action must be %q, %q or %q, got: %q",
+			models.DraftActionCreate, models.DraftActionUpdate, models.DraftActionDelete, change.Action)
+	}
+}
+
+func (s *svc) checkDraftItem(item *models.MenuItem, ingredients []models.MenuItemIngredient) error {
+	if err := helper.IsValidName(item.Name); err != nil {
+		return err
+	}
+	if err := s.resolveCategories(item); err != nil {
+		return err
+	}
+	if len(ingredients) == 0 {
+		return nil
+	}
+	inventory, err := s.Repo.InventoryRepo.GetInventory()
+	if err != nil {
+		s.Log.Error("Failed to get existing inventory items", "error", err.Error())
+		return err
+	}
+	return helper.CheckerForMenuItems(*item, inventory, ingredients)
+}
+
+// checkNotInBundles запрещает удалять компонент набора. Наборы ищутся в меню
+// вместе с черновиком: если черновик уже убирает позицию из набора, удалять можно.
+func (s *svc) checkNotInBundles(id int) error {
+	items, err := s.liveMenuSnapshot()
+	if err != nil {
+		return err
+	}
+	changes, err := s.Repo.MenuRepo.GetDraftChanges()
+	if err != nil {
+		s.Log.Error("Failed to retrieve draft changes", "error", err.Error())
+		return err
+	}
+
+	var bundles []string
+	for _, item := range previewDraft(items, changes) {
+		for _, c := range item.Components {
+			if c.MenuItemID == id {
+				bundles = append(bundles, item.Name)
+				break
+			}
+		}
+	}
+	if len(bundles) > 0 {
+		sort.Strings(bundles)
+		return fmt.Errorf("cannot delete menu item with ID %d because it is a component of bundles %s: %w",
+			id, strings.Join(bundles, ", "), cerrors.ErrInUse)
+	}
+	return nil
+}
+
+// liveMenuSnapshot — текущее меню в том виде, в каком оно сохраняется в версиях.
+func (s *svc) liveMenuSnapshot() ([]models.MenuItem, error) {
+	items, err := s.GetAllMenuItems()
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := s.Repo.MenuRepo.GetAllIngredients()
+	if err != nil {
+		s.Log.Error("Failed to retrieve recipes", "error", err.Error())
+		return nil, err
+	}
+	components, err := s.Repo.MenuRepo.GetBundleComponents()
+	if err != nil {
+		s.Log.Error("Failed to retrieve bundle components", "error", err.Error())
+		return nil, err
+	}
+	for i := range items {
+		items[i].Ingredients = recipes[items[i].ID]
+		items[i].Components = components[items[i].ID]
+	}
+	return items, nil
+}
+
+// previewDraft применяет изменения черновика к копии меню. Новые позиции ещё не имеют id.
+func previewDraft(items []models.MenuItem, changes []models.MenuDraftChange) []models.MenuItem {
+	preview := make([]models.MenuItem, 0, len(items)+len(changes))
+	preview = append(preview, items...)
+
+	for _, c := range changes {
+		switch c.Action {
+		case models.DraftActionCreate:
+			preview = append(preview, *c.Item)
+		case models.DraftActionUpdate:
+			for i := range preview {
+				if preview[i].ID != *c.MenuItemID {
+					continue
+				}
+				updated := *c.Item
+				updated.ID, updated.Type, updated.SoldOut = preview[i].ID, preview[i].Type, preview[i].SoldOut
+				if updated.Ingredients == nil {
+					updated.Ingredients = preview[i].Ingredients
+				}
+				if updated.Components == nil {
+					updated.Components = preview[i].Components
+				}
+				preview[i] = updated
+			}
+		case models.DraftActionDelete:
+			for i := range preview {
+				if preview[i].ID == *c.MenuItemID {
+					preview = append(preview[:i], preview[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return preview
+}
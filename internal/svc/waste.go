@@ -0,0 +1,56 @@
+package svc
+
+import (
+	"fmt"
+	"math"
+
+	"frappuccino/helper"
+	"frappuccino/internal/models"
+)
+
+// RecordWaste списывает испорченный или пролитый товар через журнал.
+func (s *svc) RecordWaste(ingredientID int, entry models.WasteEntry) (*models.InventoryTransaction, error) {
+	if entry.Quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be greater than 0, got: %v", entry.Quantity)
+	}
+	if err := helper.CheckWasteReason(entry.Reason); err != nil {
+		return nil, err
+	}
+
+	return s.RecordInventoryTransaction(ingredientID, models.InventoryTransaction{
+		ChangeAmount: -entry.Quantity,
+		Type:         models.TransactionWaste,
+		Reason:       entry.Reason,
+		Note:         entry.Note,
+		LocationID:   entry.LocationID,
+	})
+}
+
+func (s *svc) GetWasteReport(filter models.WasteFilter) (*models.WasteReport, error) {
+	switch filter.Period {
+	case "", "day", "week", "month":
+	default:
+		return nil, fmt.Errorf("invalid period: %s, expected day, week or month", filter.Period)
+	}
+
+	items, err := s.Repo.InventoryRepo.GetWasteSummary(filter)
+	if err != nil {
+		s.Log.Error("Failed to retrieve waste summary", "error", err.Error())
+		return nil, err
+	}
+
+	report := &models.WasteReport{
+		StartDate: filter.StartDate,
+		EndDate:   filter.EndDate,
+		Period:    filter.Period,
+		Items:     []models.WasteSummary{},
+	}
+	for _, item := range items {
+		item.Quantity = math.Round(item.Quantity*10000) / 10000
+		item.Cost = math.Round(item.Cost*100) / 100
+		report.TotalCost += item.Cost
+		report.Items = append(report.Items, item)
+	}
+	report.TotalCost = math.Round(report.TotalCost*100) / 100
+	return report, nil
+}
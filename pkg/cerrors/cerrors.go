@@ -12,6 +12,8 @@ var (
 	ErrIsNotEmpty       = errors.New("is not empty")
 	ErrOrderNotFound    = errors.New("order id not found")
 	ErrMenuItemNotFound = errors.New("menu item id not found")
+	ErrInUse            = errors.New("is in use")
+	ErrInvalidParam     = errors.New("invalid parameter")
 )
 
 func NotExist() error {
@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Email отправляет уведомление письмом через SMTP без авторизации — для
+// локального релея или заглушки вроде Mailpit.
+type Email struct {
+	Addr string
+	From string
+	To   []string
+}
+
+func (n Email) Notify(msg Message) error {
+	from := n.From
+	if from == "" {
+		from = "frappuccino@localhost"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Text, "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	if err := smtp.SendMail(n.Addr, nil, from, n.To, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Message — уведомление для персонала. Data уходит в вебхук как есть,
+// для лога и почты хватает Subject и Text.
+type Message struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	Data    any    `json:"data,omitempty"`
+}
+
+type Notifier interface {
+	Notify(msg Message) error
+}
+
+// Log пишет уведомления в журнал приложения.
+type Log struct {
+	Logger *slog.Logger
+}
+
+func (n Log) Notify(msg Message) error {
+	logger := n.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn(msg.Subject, "text", msg.Text)
+	return nil
+}
+
+// Multi рассылает уведомление всем получателям; ошибка одного не мешает остальным.
+type Multi []Notifier
+
+func (m Multi) Notify(msg Message) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Config — настройки каналов. Channels — список через запятую: log, webhook, email.
+type Config struct {
+	Channels   string
+	WebhookURL string
+	SMTPAddr   string
+	EmailFrom  string
+	EmailTo    string
+}
+
+func New(cfg Config, logger *slog.Logger) (Notifier, error) {
+	var result Multi
+	for _, channel := range strings.Split(cfg.Channels, ",") {
+		switch strings.ToLower(strings.TrimSpace(channel)) {
+		case "":
+		case "log":
+			result = append(result, Log{Logger: logger})
+		case "webhook":
+			if cfg.WebhookURL == "" {
+				return nil, fmt.Errorf("webhook notifier requires a webhook URL")
+			}
+			result = append(result, NewWebhook(cfg.WebhookURL))
+		case "email":
+			if cfg.SMTPAddr == "" || cfg.EmailTo == "" {
+				return nil, fmt.Errorf("email notifier requires an SMTP address and a recipient")
+			}
+			result = append(result, Email{Addr: cfg.SMTPAddr, From: cfg.EmailFrom, To: strings.Split(cfg.EmailTo, ",")})
+		default:
+			return nil, fmt.Errorf("unknown notifier %q, expected log, webhook or email", channel)
+		}
+	}
+	if len(result) == 0 {
+		return Log{Logger: logger}, nil
+	}
+	return result, nil
+}
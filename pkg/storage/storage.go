@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("file not found")
+
+// Storage — хранилище загруженных файлов. Имена файлов плоские, без каталогов;
+// URL возвращает адрес, по которому файл отдаётся клиентам.
+type Storage interface {
+	Save(name string, r io.Reader) error
+	Open(name string) (io.ReadSeekCloser, error)
+	Delete(name string) error
+	URL(name string) string
+}
@@ -0,0 +1,94 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Dimension string
+
+const (
+	Mass   Dimension = "mass"
+	Volume Dimension = "volume"
+	Count  Dimension = "count"
+)
+
+var (
+	ErrUnknownUnit  = errors.New("unknown unit")
+	ErrIncompatible = errors.New("units are not convertible")
+)
+
+// Unit — единица измерения. Factor переводит её в базовую единицу измерения:
+// граммы для массы, миллилитры для объёма, штуки для счёта.
+type Unit struct {
+	Name      string    `json:"name"`
+	Dimension Dimension `json:"dimension"`
+	Factor    float64   `json:"factor"`
+	Aliases   []string  `json:"aliases,omitempty"`
+}
+
+var known = []Unit{
+	{Name: "mg", Dimension: Mass, Factor: 0.001, Aliases: []string{"milligram", "milligrams"}},
+	{Name: "g", Dimension: Mass, Factor: 1, Aliases: []string{"gr", "gram", "grams"}},
+	{Name: "kg", Dimension: Mass, Factor: 1000, Aliases: []string{"kilogram", "kilograms"}},
+	{Name: "oz", Dimension: Mass, Factor: 28.349523125, Aliases: []string{"ounce", "ounces"}},
+	{Name: "lb", Dimension: Mass, Factor: 453.59237, Aliases: []string{"lbs", "pound", "pounds"}},
+
+	{Name: "ml", Dimension: Volume, Factor: 1, Aliases: []string{"milliliter", "milliliters", "millilitre", "millilitres"}},
+	{Name: "cl", Dimension: Volume, Factor: 10},
+	{Name: "l", Dimension: Volume, Factor: 1000, Aliases: []string{"liter", "liters", "litre", "litres"}},
+	{Name: "tsp", Dimension: Volume, Factor: 5, Aliases: []string{"teaspoon", "teaspoons"}},
+	{Name: "tbsp", Dimension: Volume, Factor: 15, Aliases: []string{"tablespoon", "tablespoons"}},
+	{Name: "fl oz", Dimension: Volume, Factor: 29.5735295625, Aliases: []string{"floz", "fluid ounce", "fluid ounces"}},
+	{Name: "cup", Dimension: Volume, Factor: 240, Aliases: []string{"cups"}},
+
+	{Name: "pcs", Dimension: Count, Factor: 1, Aliases: []string{"pc", "piece", "pieces", "each", "ea", "unit", "units"}},
+	{Name: "dozen", Dimension: Count, Factor: 12, Aliases: []string{"dz"}},
+}
+
+var byName = func() map[string]Unit {
+	m := make(map[string]Unit)
+	for _, u := range known {
+		m[u.Name] = u
+		for _, alias := range u.Aliases {
+			m[alias] = u
+		}
+	}
+	return m
+}()
+
+// All возвращает известные единицы, сгруппированные по измерению.
+func All() []Unit {
+	result := make([]Unit, len(known))
+	copy(result, known)
+	return result
+}
+
+// Parse находит единицу по имени или синониму без учёта регистра.
+func Parse(name string) (Unit, error) {
+	u, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Unit{}, fmt.Errorf("%w: %q", ErrUnknownUnit, name)
+	}
+	return u, nil
+}
+
+// Convert переводит количество из одной единицы в другую того же измерения.
+func Convert(quantity float64, from, to string) (float64, error) {
+	fromUnit, err := Parse(from)
+	if err != nil {
+		return 0, err
+	}
+	toUnit, err := Parse(to)
+	if err != nil {
+		return 0, err
+	}
+	if fromUnit.Dimension != toUnit.Dimension {
+		return 0, fmt.Errorf("%w: %s (%s) and %s (%s)", ErrIncompatible, fromUnit.Name, fromUnit.Dimension, toUnit.Name, toUnit.Dimension)
+	}
+	if fromUnit.Name == toUnit.Name {
+		return quantity, nil
+	}
+	return quantity * fromUnit.Factor / toUnit.Factor, nil
+}